@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rduarte/skl/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Gerencia o cache local de repositórios",
+	Long: `Os repositórios de skills são mantidos como clones "bare" em ~/.cache/skl/
+e atualizados incrementalmente, evitando clonar o mesmo repositório a cada
+install, update, info ou list.`,
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lista os repositórios em cache",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheList,
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove todo o cache de repositórios",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheClean,
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove repositórios não utilizados há mais de N dias",
+	Args:  cobra.NoArgs,
+	RunE:  runCachePrune,
+}
+
+var pruneDays int
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheListCmd, cacheCleanCmd, cachePruneCmd)
+	cachePruneCmd.Flags().IntVar(&pruneDays, "days", 30, "Remove repositórios não utilizados há mais dias que este valor")
+}
+
+func runCacheList(cmd *cobra.Command, args []string) error {
+	entries, err := cache.List()
+	if err != nil {
+		return fmt.Errorf("erro ao listar cache: %w", err)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("ℹ️  O cache está vazio.")
+		return nil
+	}
+
+	dir, _ := cache.Dir()
+	fmt.Printf("📦 Repositórios em cache (%s):\n\n", dir)
+
+	var total int64
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REPOSITÓRIO\tTAMANHO\tÚLTIMO USO")
+	fmt.Fprintln(w, "-----------\t-------\t----------")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e.URL, formatSize(e.Size), e.LastUsed.Format("2006-01-02 15:04"))
+		total += e.Size
+	}
+	w.Flush()
+
+	fmt.Printf("\nTotal: %d repositório(s), %s\n", len(entries), formatSize(total))
+	return nil
+}
+
+func runCacheClean(cmd *cobra.Command, args []string) error {
+	if err := cache.Clean(); err != nil {
+		return fmt.Errorf("erro ao limpar cache: %w", err)
+	}
+	fmt.Println("🧹 Cache removido")
+	return nil
+}
+
+func runCachePrune(cmd *cobra.Command, args []string) error {
+	removed, err := cache.Prune(time.Duration(pruneDays) * 24 * time.Hour)
+	for _, e := range removed {
+		fmt.Printf("🗑️  %s\n", e.URL)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao limpar cache: %w", err)
+	}
+
+	if len(removed) == 0 {
+		fmt.Printf("✅ Nenhum repositório sem uso há mais de %d dia(s)\n", pruneDays)
+		return nil
+	}
+
+	fmt.Printf("🧹 %d repositório(s) removido(s) do cache\n", len(removed))
+	return nil
+}
+
+// formatSize renders a byte count in a human-readable unit.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
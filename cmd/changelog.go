@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rduarte/skl/internal/installer"
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/rduarte/skl/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog <skill-name>",
+	Short: "Lista os commits que alteraram uma skill desde a versão instalada",
+	Long: `Mostra os commits do repositório de origem que alteraram o diretório da
+skill entre o commit registrado no sklfile.lock e a versão para a qual o
+sklfile.json aponta agora (a que o 'skl update' instalaria), seguidos de um
+resumo dos arquivos alterados.
+
+Exemplos:
+  skl changelog data-analyzer
+  skl changelog data-analyzer --to v2.0.0`,
+	Args:              cobra.ExactArgs(1),
+	SilenceUsage:      true,
+	ValidArgsFunction: completeRemoteSkills,
+	RunE:              runChangelog,
+}
+
+// completeRemoteSkills suggests the installed skills that come from a
+// repository (local@ skills are left out).
+func completeRemoteSkills(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	lock, err := manifest.LoadLock()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var suggestions []string
+	for source, entry := range lock.Skills {
+		name := entry.DirName(source)
+		if !strings.HasPrefix(source, "local@") && strings.HasPrefix(name, toComplete) {
+			suggestions = append(suggestions, name)
+		}
+	}
+
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+var changelogTo string
+
+func init() {
+	rootCmd.AddCommand(changelogCmd)
+	changelogCmd.Flags().StringVar(&changelogTo, "to", "", "Compara com outra branch, tag ou faixa semver em vez da versão do sklfile.json")
+}
+
+func runChangelog(cmd *cobra.Command, args []string) error {
+	skill := args[0]
+
+	mf, err := manifest.Load()
+	if err != nil {
+		return err
+	}
+	lock, err := manifest.LoadLock()
+	if err != nil {
+		return err
+	}
+
+	source, err := findSkill(lock, skill)
+	if err != nil {
+		return err
+	}
+	if source == "" {
+		return fmt.Errorf("skill %q não encontrada no %s", skill, manifest.LockFileName)
+	}
+	locked := lock.Skills[source]
+	skill = locked.DirName(source)
+
+	entry, ok := mf.Skills[source]
+	if changelogTo != "" {
+		entry = locked
+		entry.Ref = changelogTo
+		entry.Version = ""
+	} else if !ok {
+		return fmt.Errorf("skill %q não está no %s; use --to para escolher a versão de destino", skill, manifest.FileName)
+	}
+
+	target, err := resolveDesired(source, entry)
+	if err != nil {
+		return err
+	}
+	if target.Version == "" && changelogTo != "" {
+		target.Version = changelogTo // label the target with the ref asked for
+	}
+
+	if target.Ref == locked.Ref {
+		fmt.Printf("✅ %q já está em %s\n", skill, commitLabel(locked.Ref, locked.Version))
+		return nil
+	}
+
+	return printChangelog(os.Stdout, source, locked, target)
+}
+
+// skillChanges returns the upstream commits of a skill between two lock
+// entries (the commit hash is in Ref).
+func skillChanges(source string, from, to manifest.Entry) (*installer.Changes, error) {
+	ref, err := parser.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Provider == parser.ProviderLocal || ref.Provider == parser.ProviderFile {
+		return nil, fmt.Errorf("%q não vem de um repositório git, não há histórico", source)
+	}
+
+	cloneURL, repoURL, err := remoteURLs(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve skill path (manifest entry, git+ fragment or catalog.json)
+	ref.Tag = to.Ref
+	overridePath := to.Path
+	if overridePath == "" {
+		overridePath = skillRepoPath(ref)
+	}
+
+	return installer.Changelog(cloneURL, repoURL, ref.Skill, overridePath, from.Ref, to.Ref)
+}
+
+// printChangelog writes the commits and the diffstat of a skill between two
+// lock entries to w.
+func printChangelog(w io.Writer, source string, from, to manifest.Entry) error {
+	changes, err := skillChanges(source, from, to)
+	if err != nil {
+		return err
+	}
+	printChanges(w, to.DirName(source), from.Ref, from.Version, to.Ref, to.Version, changes)
+	return nil
+}
+
+// printChanges renders a changelog between two commits (and their tags).
+func printChanges(w io.Writer, skill, fromHash, fromTag, toHash, toTag string, changes *installer.Changes) {
+	fmt.Fprintf(w, "📜 %s: %s → %s (%s)\n", skill, commitLabel(fromHash, fromTag), commitLabel(toHash, toTag), changes.Path)
+	if changes.Downgrade {
+		fmt.Fprintln(w, "   ⚠️  Versão anterior à instalada: os commits abaixo serão desfeitos")
+	}
+
+	if len(changes.Commits) == 0 {
+		fmt.Fprintf(w, "   Nenhum commit alterou %s\n", changes.Path)
+	}
+	for _, c := range changes.Commits {
+		fmt.Fprintf(w, "   %s %s %s (%s)\n", c.Hash[:7], c.Date, c.Subject, c.Author)
+	}
+
+	if changes.DiffStat != "" {
+		fmt.Fprintln(w)
+		for _, line := range strings.Split(changes.DiffStat, "\n") {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+}
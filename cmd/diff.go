@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rduarte/skl/internal/installer"
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/rduarte/skl/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [skill-name]",
+	Short: "Mostra as alterações locais de uma skill em relação à versão instalada",
+	Long: `Busca no repositório de origem a revisão exata registrada no sklfile.lock
+e mostra um diff unificado entre ela e a cópia instalada da skill.
+
+Sem argumentos, compara todas as skills que foram alteradas desde a
+instalação (veja skl verify). Útil para decidir se uma alteração local deve
+ser enviada ao repositório de origem ou se a skill deve virar local.
+
+Exemplos:
+  skl diff
+  skl diff data-analyzer
+  skl diff data-analyzer --stat`,
+	Args:              cobra.MaximumNArgs(1),
+	SilenceUsage:      true,
+	ValidArgsFunction: completeRemoteSkills,
+	RunE:              runDiff,
+}
+
+var diffStat bool
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().BoolVar(&diffStat, "stat", false, "Mostra apenas o resumo dos arquivos alterados")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	lock, err := manifest.LoadLock()
+	if err != nil {
+		return err
+	}
+
+	var sources []string
+	if len(args) == 1 {
+		source, err := findSkill(lock, args[0])
+		if err != nil {
+			return err
+		}
+		if source == "" {
+			return fmt.Errorf("skill %q não encontrada no %s", args[0], manifest.LockFileName)
+		}
+		sources = []string{source}
+	} else {
+		for _, source := range lock.SortedSources() {
+			if ref, err := parser.Parse(source); err == nil &&
+				(ref.Provider == parser.ProviderLocal || ref.Provider == parser.ProviderFile) {
+				continue
+			}
+
+			// Skip what is known to be intact, no need to reach the remote
+			if sum := lock.Integrity[source]; sum != nil {
+				state, _, err := checkSkill(source, lock.Skills[source], sum)
+				if err == nil && state == skillIntact {
+					continue
+				}
+			}
+			sources = append(sources, source)
+		}
+		if len(sources) == 0 {
+			fmt.Println("✅ Nenhuma skill foi alterada localmente")
+			return nil
+		}
+	}
+
+	for i, source := range sources {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := printLocalDiff(source, lock.Skills[source]); err != nil {
+			if len(sources) == 1 {
+				return err
+			}
+			fmt.Printf("⚠️  %s: %v\n", lock.Skills[source].DirName(source), err)
+		}
+	}
+	return nil
+}
+
+// printLocalDiff shows the diff between the locked upstream revision of a
+// skill (or the one an ejected skill came from) and its installed copy.
+func printLocalDiff(source string, entry manifest.Entry) error {
+	skill := entry.DirName(source)
+
+	var diffArgs []string
+	if diffStat {
+		diffArgs = append(diffArgs, "--stat")
+	}
+	diff, err := localDiff(source, entry, diffArgs...)
+	if err != nil {
+		return err
+	}
+
+	base := commitLabel(entry.Ref, entry.Version)
+	if origin := entry.Origin; origin.Source != "" {
+		base = origin.Source + " " + commitLabel(origin.Commit, origin.Version)
+	}
+
+	if strings.TrimSpace(diff) == "" {
+		fmt.Printf("✅ %s: sem alterações locais em relação a %s\n", skill, base)
+		return nil
+	}
+
+	fmt.Printf("📝 %s: alterações locais em relação a %s\n\n", skill, base)
+	fmt.Print(diff)
+	return nil
+}
+
+// localDiff returns the unified diff from the locked upstream revision of a
+// skill to its installed copy. Extra args are passed to git diff. Ejected
+// local skills are compared with the revision they were ejected from.
+func localDiff(source string, entry manifest.Entry, args ...string) (string, error) {
+	dir, err := installedPath(source, entry)
+	if err != nil {
+		return "", err
+	}
+
+	if origin := entry.Origin; origin.Source != "" {
+		source = origin.Source
+		entry = manifest.Entry{Ref: origin.Commit, Path: origin.Path}
+	}
+
+	ref, err := parser.Parse(source)
+	if err != nil {
+		return "", err
+	}
+	if ref.Provider == parser.ProviderLocal || ref.Provider == parser.ProviderFile {
+		return "", fmt.Errorf("%q não vem de um repositório git, não há versão de origem para comparar", source)
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return "", fmt.Errorf("skill %q não está instalada (execute skl update)", filepath.Base(dir))
+	}
+
+	cloneURL, repoURL, err := remoteURLs(ref)
+	if err != nil {
+		return "", err
+	}
+
+	// Resolve skill path (manifest entry, git+ fragment or catalog.json)
+	ref.Tag = entry.Ref
+	overridePath := entry.Path
+	if overridePath == "" {
+		overridePath = skillRepoPath(ref)
+	}
+
+	return installer.DiffLocal(cloneURL, repoURL, ref.Skill, entry.Ref, overridePath, dir, args...)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/rduarte/skl/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var ejectCmd = &cobra.Command{
+	Use:     "eject <skill-name>",
+	Aliases: []string{"fork"},
+	Short:   "Converte uma skill remota instalada em uma skill local",
+	Long: `Mantém os arquivos da skill instalada e passa a tratá-la como local@<skill>:
+o sklfile.json e o sklfile.lock deixam de apontar para o repositório de
+origem, e o update não a altera mais.
+
+A referência original e o commit instalado ficam registrados em "origin",
+para comparar a cópia local com a origem (skl diff <skill>) ou voltar a
+instalá-la do repositório no futuro.
+
+Exemplos:
+  skl eject data-analyzer
+  skl fork data-analyzer`,
+	Args:              cobra.ExactArgs(1),
+	SilenceUsage:      true,
+	ValidArgsFunction: completeRemoteSkills,
+	RunE:              runEject,
+}
+
+func init() {
+	rootCmd.AddCommand(ejectCmd)
+}
+
+func runEject(cmd *cobra.Command, args []string) error {
+	skill := args[0]
+
+	mf, err := manifest.Load()
+	if err != nil {
+		return fmt.Errorf("erro ao carregar %s: %w", manifest.FileName, err)
+	}
+	lock, err := manifest.LoadLock()
+	if err != nil {
+		return fmt.Errorf("erro ao carregar %s: %w", manifest.LockFileName, err)
+	}
+
+	source, err := findSkill(mf, skill)
+	if err != nil {
+		return err
+	}
+	if source == "" {
+		return fmt.Errorf("skill %q não encontrada no %s", skill, manifest.FileName)
+	}
+	entry := mf.Skills[source]
+	skill = entry.DirName(source)
+
+	ref, err := parser.Parse(source)
+	if err != nil {
+		return err
+	}
+	switch ref.Provider {
+	case parser.ProviderLocal:
+		return fmt.Errorf("skill %q já é local", skill)
+	case parser.ProviderFile:
+		return fmt.Errorf("skill %q vem do sistema de arquivos (%s), não de um repositório remoto", skill, ref.Location)
+	}
+
+	// The origin is the revision actually installed, not what the ref
+	// points to now
+	locked, ok := lock.Skills[source]
+	if !ok {
+		return fmt.Errorf("skill %q não está instalada (execute skl update)", skill)
+	}
+	dir, err := installedPath(source, locked)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("skill %q não está instalada (execute skl update)", skill)
+	}
+
+	localSource := "local@" + skill
+	if _, exists := mf.Skills[localSource]; exists {
+		return fmt.Errorf("já existe uma skill %q no %s", localSource, manifest.FileName)
+	}
+
+	// The directory stays where it is: keep the target, drop the alias
+	// (the local name already is the directory name)
+	ejected := manifest.Entry{
+		Ref:    "*",
+		Target: entry.Target,
+		Notes:  entry.Notes,
+		Origin: manifest.Origin{
+			Source:  source,
+			Ref:     entry.Ref,
+			Commit:  locked.Ref,
+			Version: locked.Version,
+			Path:    entry.Path,
+		},
+	}
+
+	mf.Remove(source)
+	if err := mf.Add(localSource, ejected); err != nil {
+		return fmt.Errorf("erro ao atualizar %s: %w", manifest.FileName, err)
+	}
+	lock.Remove(source)
+	lock.Skills[localSource] = ejected.Locked(ejected.Ref, "")
+	if err := lock.SaveLock(); err != nil {
+		return fmt.Errorf("erro ao atualizar %s: %w", manifest.LockFileName, err)
+	}
+
+	fmt.Printf("📋 %s → %s (origem: %s)\n", source, localSource, commitLabel(locked.Ref, locked.Version))
+	if entry.Patch != "" {
+		fmt.Printf("🩹 O patch %s já está aplicado aos arquivos e não será mais usado\n", entry.Patch)
+	}
+	fmt.Printf("✅ Skill %q agora é local — compare com a origem usando: skl diff %s\n", skill, skill)
+	return nil
+}
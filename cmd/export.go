@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rduarte/skl/internal/export"
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var exportFormat string
+
+var exportCmd = &cobra.Command{
+	Use:   "export [--format <formato>]",
+	Short: "Gera os arquivos de outros assistentes a partir das skills instaladas",
+	Long: `Lê o SKILL.md de cada skill instalada (sklfile.lock) e gera os arquivos
+no formato que cada assistente lê:
+
+` + exportFormats() + `
+Arquivos compartilhados com texto escrito à mão (AGENTS.md,
+copilot-instructions.md) só têm o trecho entre <!-- skl:begin --> e
+<!-- skl:end --> substituído. Arquivos gerados para skills que não estão mais
+instaladas são apagados.
+
+Sem --format, regenera o índice configurado em "index" no sklfile.json (o
+mesmo que install, update, remove e setup atualizam).
+
+Exemplos:
+  skl export --format cursor
+  skl export --format copilot,agents-md`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "Formato(s) a gerar, separados por vírgula ("+strings.Join(export.Names(), ", ")+")")
+	exportCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return export.Names(), cobra.ShellCompDirectiveNoFileComp
+	})
+	rootCmd.AddCommand(exportCmd)
+}
+
+// exportFormats describes the registered formats, for the help text.
+func exportFormats() string {
+	var b strings.Builder
+	for _, name := range export.Names() {
+		f, _ := export.New(name)
+		fmt.Fprintf(&b, "  %-10s %s\n", name, f.Description())
+	}
+	return b.String()
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportFormat == "" {
+		// Without a format, regenerate the index of sklfile.json
+		mf, err := manifest.Load()
+		if err != nil {
+			return fmt.Errorf("erro ao carregar %s: %w", manifest.FileName, err)
+		}
+		if mf.Index == "" {
+			return fmt.Errorf("informe o formato com --format (disponíveis: %s)", strings.Join(export.Names(), ", "))
+		}
+		refreshIndex(mf.Index)
+		return nil
+	}
+
+	var formats []export.Format
+	names := strings.Split(exportFormat, ",")
+	for _, name := range names {
+		f, err := export.New(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		formats = append(formats, f)
+	}
+
+	lock, err := manifest.LoadLock()
+	if err != nil {
+		return fmt.Errorf("erro ao carregar %s: %w", manifest.LockFileName, err)
+	}
+	skills := installedSkills(lock)
+
+	for i, f := range formats {
+		written, removed, err := export.Write(f.Files(skills), f.Owned())
+		for _, path := range written {
+			fmt.Printf("📝 %s\n", path)
+		}
+		for _, path := range removed {
+			fmt.Printf("🗑️  %s (skill não instalada)\n", path)
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao exportar %s: %w", strings.TrimSpace(names[i]), err)
+		}
+	}
+
+	fmt.Printf("✅ %d skill(s) exportada(s)\n", len(skills))
+	return nil
+}
+
+// installedSkills reads the SKILL.md of every skill in lock, from its
+// tracked copy. Skills without one are skipped with a warning.
+func installedSkills(lock *manifest.Manifest) []export.Skill {
+	var skills []export.Skill
+	for _, source := range lock.SortedSources() {
+		entry := lock.Skills[source]
+		skill, err := export.ReadSkill(skillPaths(source, entry)[0])
+		if err != nil {
+			if os.IsNotExist(err) {
+				fmt.Printf("⚠️  %q não tem SKILL.md (ou não está instalada) — ignorada\n", entry.DirName(source))
+			} else {
+				fmt.Printf("⚠️  Não foi possível ler a skill %q: %v\n", entry.DirName(source), err)
+			}
+			continue
+		}
+
+		skill.Source = source
+		if !strings.HasPrefix(source, "local@") {
+			skill.Version = commitLabel(entry.Ref, entry.Version)
+		}
+		skills = append(skills, skill)
+	}
+
+	sort.Slice(skills, func(i, j int) bool { return skills[i].Name < skills[j].Name })
+	return skills
+}
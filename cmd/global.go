@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+// globalMode is set by -g/--global: the command works on the user-level
+// skills (manifest.GlobalDir) instead of the ones of the project.
+var globalMode bool
+
+// globalSkillsDir is where global skills are installed unless the global
+// sklfile.json lists other targets (e.g. an absolute ~/.claude/skills).
+const globalSkillsDir = "skills"
+
+// addGlobalFlag adds -g/--global to a command that can work on the
+// global skills. writes tells whether the command changes the skills.
+func addGlobalFlag(cmd *cobra.Command, writes bool) {
+	cmd.Flags().BoolVarP(&globalMode, "global", "g", false, "Usa as skills globais do usuário ($XDG_DATA_HOME/skl) em vez das do projeto")
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if dryRun, err := cmd.Flags().GetBool("dry-run"); err == nil && dryRun {
+			writes = false
+		}
+		return enterGlobal(writes)
+	}
+}
+
+// enterGlobal moves the process to the global directory when -g is set, so
+// the manifest, the lock and the skills are read and written there. The
+// first command that writes creates a sklfile.json installing into skills/;
+// until then the global skills are just empty.
+func enterGlobal(writes bool) error {
+	if !globalMode {
+		return nil
+	}
+
+	dir, err := manifest.GlobalDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("erro ao criar %s: %w", dir, err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(manifest.FileName); os.IsNotExist(err) && writes {
+		mf := &manifest.Manifest{Targets: []string{globalSkillsDir}, Skills: make(map[string]manifest.Entry)}
+		if err := mf.Save(); err != nil {
+			return fmt.Errorf("erro ao criar o %s global: %w", manifest.FileName, err)
+		}
+	}
+	return nil
+}
+
+// globalSkills loads the global manifest and lock, for commands run in a
+// project. Both are empty when there are no global skills.
+func globalSkills() (root string, mf, lock *manifest.Manifest, err error) {
+	if root, err = manifest.GlobalDir(); err != nil {
+		return "", nil, nil, err
+	}
+	if mf, err = manifest.LoadIn(root); err != nil {
+		return "", nil, nil, err
+	}
+	if lock, err = manifest.LoadLockIn(root); err != nil {
+		return "", nil, nil, err
+	}
+	return root, mf, lock, nil
+}
+
+// globalSkillDir returns the directory of the global skill installed under
+// name, if there is exactly one.
+func globalSkillDir(name string) (string, bool) {
+	root, mf, lock, err := globalSkills()
+	if err != nil {
+		return "", false
+	}
+	sources := lock.FindAll(name)
+	if len(sources) != 1 {
+		return "", false
+	}
+
+	entry := lock.Skills[sources[0]]
+	dir := mf.SkillsDirs(entry)[0]
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	return filepath.Join(dir, entry.DirName(sources[0])), true
+}
+
+// shadowNotice tells, after installing name, that a skill with the same
+// name exists on the other level. The project always takes precedence: its
+// skill shadows the global one.
+func shadowNotice(name string) {
+	if globalMode {
+		lock, err := manifest.LoadLockIn(manifest.FindRoot(invocationDir))
+		if err == nil && len(lock.FindAll(name)) > 0 {
+			fmt.Printf("ℹ️  Neste projeto, a skill %q do projeto tem precedência sobre a global\n", name)
+		}
+		return
+	}
+
+	if _, _, lock, err := globalSkills(); err == nil && len(lock.FindAll(name)) > 0 {
+		fmt.Printf("ℹ️  A skill global %q fica sobreposta neste projeto (a do projeto tem precedência)\n", name)
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/rduarte/skl/internal/export"
+	"github.com/rduarte/skl/internal/manifest"
+)
+
+// refreshIndex regenerates the skills index configured in sklfile.json
+// ("index"), between the skl markers of the file. It does nothing when no
+// index is configured; failures are only reported, the command that
+// changed the skills already succeeded.
+func refreshIndex(path string) {
+	if path == "" {
+		return
+	}
+
+	lock, err := manifest.LoadLock()
+	if err != nil {
+		fmt.Printf("⚠️  Não foi possível atualizar o índice %s: %v\n", path, err)
+		return
+	}
+
+	file := export.File{
+		Path:    path,
+		Content: export.Index(installedSkills(lock), "o skl o atualiza a cada install, update, remove e setup"),
+		Block:   true,
+	}
+	if _, _, err := export.Write([]export.File{file}, nil); err != nil {
+		fmt.Printf("⚠️  Não foi possível atualizar o índice %s: %v\n", path, err)
+		return
+	}
+	fmt.Printf("📝 Índice de skills atualizado: %s\n", path)
+}
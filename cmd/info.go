@@ -76,8 +76,8 @@ Uso com referência remota (sem instalar):
 		} else {
 			lock, err := manifest.LoadLock()
 			if err == nil && lock != nil {
-				for source := range lock.Skills {
-					name := manifest.SkillName(source)
+				for source, entry := range lock.Skills {
+					name := entry.DirName(source)
 					if strings.HasPrefix(name, toComplete) {
 						suggestions = append(suggestions, name)
 					}
@@ -91,16 +91,17 @@ Uso com referência remota (sem instalar):
 }
 
 func init() {
+	addGlobalFlag(infoCmd, false)
 	rootCmd.AddCommand(infoCmd)
 }
 
 func runInfo(cmd *cobra.Command, args []string) error {
-	arg := args[0]
+	arg := fromInvocationDir(args[0])
 
 	var data []byte
 	var err error
 
-	if strings.Contains(arg, "@") {
+	if strings.Contains(arg, "@") || strings.HasPrefix(arg, "git+") || strings.HasPrefix(arg, "file:") {
 		// Remote mode: fetch SKILL.md from repo without installing
 		data, err = fetchRemoteSkillMD(arg)
 	} else {
@@ -115,16 +116,33 @@ func runInfo(cmd *cobra.Command, args []string) error {
 	return renderMarkdown(data)
 }
 
-// readLocalSkillMD reads SKILL.md from a locally installed skill.
+// readLocalSkillMD reads SKILL.md from a locally installed skill, looked up
+// by its directory name (alias or skill name) in sklfile.json.
 func readLocalSkillMD(skill string) ([]byte, error) {
-	cwd, err := os.Getwd()
+	var entry manifest.Entry
+	if mf, err := manifest.Load(); err == nil {
+		if source, err := findSkill(mf, skill); err == nil {
+			entry = mf.Skills[source]
+		}
+	}
+
+	dir, err := installer.SkillPath(skillsDirs(entry)[0], skill)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao obter diretório atual: %w", err)
+		return nil, err
 	}
 
-	skillFile := filepath.Join(cwd, ".agent", "skills", skill, "SKILL.md")
+	skillFile := filepath.Join(dir, "SKILL.md")
 
 	data, err := os.ReadFile(skillFile)
+	if os.IsNotExist(err) && !globalMode {
+		// Not in the project: fall back to a global skill
+		if dir, ok := globalSkillDir(skill); ok {
+			if global, gErr := os.ReadFile(filepath.Join(dir, "SKILL.md")); gErr == nil {
+				fmt.Printf("🌐 Skill global: %s\n\n", dir)
+				return global, nil
+			}
+		}
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("skill %q não encontrada\n\n  Arquivo esperado: %s\n  A skill está instalada? Verifique com: skl update", skill, skillFile)
@@ -136,30 +154,40 @@ func readLocalSkillMD(skill string) ([]byte, error) {
 }
 
 // fetchRemoteSkillMD fetches SKILL.md from a remote repo using sparse-checkout.
+// file: references are read straight from the filesystem.
 func fetchRemoteSkillMD(rawRef string) ([]byte, error) {
 	ref, err := parser.Parse(rawRef)
 	if err != nil {
 		return nil, err
 	}
 
-	prov, err := provider.New(ref.Provider)
+	if ref.Provider == parser.ProviderFile {
+		data, err := os.ReadFile(filepath.Join(ref.Location, "SKILL.md"))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler SKILL.md em %s: %w", ref.Location, err)
+		}
+		return data, nil
+	}
+
+	cloneURL, repoURL, err := remoteURLs(ref)
 	if err != nil {
 		return nil, err
 	}
 
-	cloneURL := prov.CloneURL(ref.User, ref.Repo)
-	repoURL := prov.RepoURL(ref.User, ref.Repo)
-
-	fmt.Printf("🔗 Clone URL: %s\n", cloneURL)
+	fmt.Printf("🔗 Clone URL: %s\n", provider.Redact(cloneURL))
 	fmt.Printf("⬇  Buscando SKILL.md de %q...\n\n", ref.Skill)
 
-	// Resolve skill path (via catalog.json if available)
+	if ref.Tag, err = resolveTag(cloneURL, ref.Tag); err != nil {
+		return nil, err
+	}
+
+	// Resolve skill path (manifest entry, git+ fragment or catalog.json)
 	var overridePath string
-	cat, err := catalog.Fetch(prov, ref.User, ref.Repo, ref.Tag)
-	if err == nil && cat != nil {
-		if entry := cat.Find(ref.Skill); entry != nil && entry.Path != "" {
-			overridePath = entry.Path
-		}
+	if mf, err := manifest.Load(); err == nil {
+		overridePath = mf.Skills[ref.Source()].Path
+	}
+	if overridePath == "" {
+		overridePath = skillRepoPath(ref)
 	}
 
 	data, err := installer.FetchFile(cloneURL, repoURL, ref.Skill, ref.Tag, overridePath, "SKILL.md")
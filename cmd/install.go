@@ -9,17 +9,24 @@ import (
 	"github.com/rduarte/skl/internal/manifest"
 	"github.com/rduarte/skl/internal/parser"
 	"github.com/rduarte/skl/internal/provider"
+	"github.com/rduarte/skl/internal/semver"
 	"github.com/spf13/cobra"
 )
 
 var installCmd = &cobra.Command{
-	Use:   "install <provider>@<user>/<repo>/<skill>[:tag]",
+	Use:   "install <provider>@<user>/<repo>/<skill>[:tag] | git+<url>#<skill>[@ref] | file:<caminho>",
 	Short: "Baixa e instala uma skill no projeto atual",
-	Long: `Baixa uma skill de um repositório Git e a instala em .agent/skills/<skill>.
+	Long: `Baixa uma skill de um repositório Git e a instala em .agent/skills/<skill>
+(ou em <target>/<as>, usando --as e --target). Com "targets" no sklfile.json, a
+skill é copiada para cada um dos diretórios listados.
 
 Exemplos:
   skl install github@empresa/repo-skills/data-analyzer:v1.2.0
-  skl install bitbucket@servicos-1doc/1doc-apis/1doc-api-expert`,
+  skl install github@empresa/repo-skills/data-analyzer:^1.2
+  skl install bitbucket@servicos-1doc/1doc-apis/1doc-api-expert
+  skl install git+https://git.empresa.com/time/skills.git#data-analyzer@v1.2.0
+  skl install file:../skills-repo/data-analyzer
+  skl install github@empresa/repo-skills/data-analyzer --as data-analyzer-legacy`,
 	Args: cobra.ExactArgs(1),
 	RunE: runInstall,
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -82,80 +89,177 @@ Exemplos:
 	},
 }
 
-var forceInstall bool
+var (
+	forceInstall  bool
+	installAs     string
+	installPath   string
+	installTarget string
+)
 
 func init() {
+	addGlobalFlag(installCmd, true)
 	rootCmd.AddCommand(installCmd)
 	installCmd.Flags().BoolVarP(&forceInstall, "force", "f", false, "Sobrescreve a skill se ela já estiver instalada")
+	installCmd.Flags().StringVar(&installAs, "as", "", "Instala a skill com outro nome de diretório")
+	installCmd.Flags().StringVar(&installPath, "path", "", "Caminho da skill dentro do repositório")
+	installCmd.Flags().StringVar(&installTarget, "target", "", "Diretório de skills relativo ao projeto (padrão: os \"targets\" do sklfile.json ou .agent/skills)")
 }
 
 func runInstall(cmd *cobra.Command, args []string) error {
 	// 1. Parse the skill reference
-	ref, err := parser.Parse(args[0])
+	ref, err := parser.Parse(fromInvocationDir(args[0]))
 	if err != nil {
 		return err
 	}
 
-	// 2. Resolve the provider
-	prov, err := provider.New(ref.Provider)
+	// Reinstalling keeps the settings already in sklfile.json; flags override them
+	source := ref.Source()
+	mf, err := manifest.Load()
 	if err != nil {
+		return fmt.Errorf("erro ao carregar %s: %w", manifest.FileName, err)
+	}
+
+	prev, reinstall := mf.Skills[source]
+	entry := prev
+	entry.Ref = ref.Tag
+	if entry.Ref == "" {
+		entry.Ref = "*"
+	}
+	if installAs != "" {
+		entry.As = installAs
+	}
+	if installPath != "" {
+		entry.Path = installPath
+	}
+	if installTarget != "" {
+		entry.Target = installTarget
+	}
+	if err := entry.Validate(source); err != nil {
 		return err
 	}
+	if entry.Target != "" {
+		if err := manifest.CheckTarget(entry.Target, globalMode); err != nil {
+			return err
+		}
+	}
+
+	// Two skills can't share a directory (e.g. "code-reviewer" from two
+	// repositories): the second one needs an alias
+	lock, err := manifest.LoadLock()
+	if err != nil {
+		return fmt.Errorf("erro ao carregar %s: %w", manifest.LockFileName, err)
+	}
+	if len(lock.Skills) == 0 {
+		// lock.Targets records where the existing copies are, so update can
+		// move them when "targets" changes. With nothing installed there are
+		// no copies elsewhere: record the current targets, or the next
+		// update would see a change of targets and resync for nothing.
+		// Otherwise the old targets are kept until update moves the copies.
+		lock.Targets = mf.Targets
+	}
+	if owner, dir := dirOwner(mf, lock, source, entry); owner != "" {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("conflito de nomes: %s já pertence a %s\n\n  Instale com outro nome de diretório: skl install %s --as <nome>",
+			dir, owner, args[0])
+	}
+
+	var cloneURL, version, commit string
+	if ref.Provider == parser.ProviderFile {
+		// 2-5. Filesystem sources are copied as-is, no clone involved
+		opts := installer.Options{
+			Skill:     entry.DirName(source),
+			SkillsDir: skillsDirs(entry)[0],
+			LocalPath: ref.Location,
+			Patch:     entry.Patch,
+			Force:     forceInstall,
+		}
+		if _, err := installer.Install(opts); err != nil {
+			return err
+		}
+	} else {
+		// 2-3. Build the clone URL and the browsable repo URL
+		var repoURL string
+		cloneURL, repoURL, err = remoteURLs(ref)
+		if err != nil {
+			return err
+		}
 
-	// 3. Build the clone URL and the browsable repo URL
-	cloneURL := prov.CloneURL(ref.User, ref.Repo)
-	repoURL := prov.RepoURL(ref.User, ref.Repo)
+		fmt.Printf("🔗 Clone URL: %s\n", provider.Redact(cloneURL))
 
-	fmt.Printf("🔗 Clone URL: %s\n", cloneURL)
+		// A semver range (e.g. ^1.2) installs the highest matching tag;
+		// sklfile.json keeps the range, sklfile.lock the tag
+		if semver.IsRange(ref.Tag) {
+			version, _, err = installer.ResolveVersion(cloneURL, ref.Tag)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("🏷️  %s → %s\n", ref.Tag, version)
+			ref.Tag = version
+		}
 
-	// 4. Resolve skill path (via catalog.json if available)
-	var overridePath string
-	cat, err := catalog.Fetch(prov, ref.User, ref.Repo, ref.Tag)
-	if err == nil && cat != nil {
-		if entry := cat.Find(ref.Skill); entry != nil && entry.Path != "" {
-			overridePath = entry.Path
-			fmt.Printf("📖 Skill localizada via catálogo: %s\n", overridePath)
+		// 4. Resolve skill path (manifest entry, git+ fragment or catalog.json)
+		overridePath := entry.Path
+		if overridePath == "" {
+			overridePath = skillRepoPath(ref)
+			if overridePath != "" && ref.Provider != parser.ProviderGit {
+				fmt.Printf("📖 Skill localizada via catálogo: %s\n", overridePath)
+			}
 		}
-	}
 
-	// 5. Install the skill (force=false: don't overwrite existing)
-	if err := installer.Install(cloneURL, repoURL, ref.Skill, ref.Tag, overridePath, forceInstall); err != nil {
-		return err
+		// 5. Install the skill (force=false: don't overwrite existing)
+		opts := installer.Options{
+			Skill:        entry.DirName(source),
+			SkillsDir:    skillsDirs(entry)[0],
+			CloneURL:     cloneURL,
+			RepoURL:      repoURL,
+			RepoSkill:    ref.Skill,
+			Tag:          ref.Tag,
+			OverridePath: overridePath,
+			Patch:        entry.Patch,
+			Force:        forceInstall,
+		}
+		if commit, err = installer.Install(opts); err != nil {
+			return err
+		}
 	}
 
-	// 6. Register in sklfile.json
-	// Key: provider@user/repo/skill  Value: tag (or empty)
-	source := fmt.Sprintf("%s@%s/%s/%s", ref.Provider, ref.User, ref.Repo, ref.Skill)
-	mf, err := manifest.Load()
-	if err != nil {
-		return fmt.Errorf("erro ao carregar %s: %w", manifest.FileName, err)
+	// Copy it to the other target directories of sklfile.json
+	if err := mirrorSkill(source, entry, false); err != nil {
+		return fmt.Errorf("erro ao copiar a skill para os outros diretórios de destino: %w", err)
 	}
 
-	gitRef := ref.Tag
-	if gitRef == "" {
-		gitRef = "*"
+	// A new alias or target moves the skill: drop the previous copy
+	if reinstall {
+		if err := removeMovedSkill(source, prev, entry); err != nil {
+			fmt.Printf("⚠️  Aviso: Não foi possível remover a cópia anterior da skill: %v\n", err)
+		}
 	}
 
-	if err := mf.Add(source, gitRef); err != nil {
+	// 6. Register in sklfile.json
+	// Key: source without tag (e.g. provider@user/repo/skill)  Value: tag (or *)
+	// plus the install settings, if any
+	if err := mf.Add(source, entry); err != nil {
 		return fmt.Errorf("erro ao registrar skill no %s: %w", manifest.FileName, err)
 	}
 
-	// 7. Update sklfile.lock with the exact commit hash
-	lock, err := manifest.LoadLock()
-	if err != nil {
-		return fmt.Errorf("erro ao carregar %s: %w", manifest.LockFileName, err)
+	// 7. Update sklfile.lock with the commit the files were checked out from
+	// Local and filesystem skills don't have a remote hash
+	if cloneURL == "" {
+		lock.Skills[source] = entry.Locked("*", "")
+	} else {
+		lock.Skills[source] = entry.Locked(commit, version)
 	}
 
-	// Local skills don't have a remote hash
-	if ref.Provider == "local" {
-		lock.Skills[source] = "*"
-	} else {
-		hash, err := installer.ResolveRef(cloneURL, ref.Tag)
-		if err != nil {
-			fmt.Printf("⚠️  Aviso: Não foi possível resolver o hash remoto para o %s: %v\n", manifest.LockFileName, err)
-			hash = gitRef // Fallback to symbolic ref
-		}
-		lock.Skills[source] = hash
+	// Record what was installed, so later edits can be detected (skl verify)
+	if err := recordIntegrity(lock, source); err != nil {
+		fmt.Printf("⚠️  Aviso: Não foi possível registrar a integridade da skill: %v\n", err)
+	}
+
+	// 8. Install the skills it requires (SKILL.md or skill.json) that are
+	// not in the project yet
+	depsErr := installDependencies(lock, source)
+	if depsErr != nil {
+		cmd.SilenceUsage = true
 	}
 
 	if err := lock.SaveLock(); err != nil {
@@ -167,5 +271,7 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	// Ensure lock file is ignored in .gitignore
 	_ = manifest.EnsureIgnoreLock()
 
-	return nil
+	shadowNotice(entry.DirName(source))
+	refreshIndex(mf.Index)
+	return depsErr
 }
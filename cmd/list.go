@@ -13,22 +13,32 @@ import (
 )
 
 var listCmd = &cobra.Command{
-	Use:   "list <provider>@<user>/<repo>",
+	Use:   "list [<provider>@<user>/<repo>]",
 	Short: "Lista todas as skills de um repositório que possui um catalog.json",
 	Long: `Busca o arquivo catalog.json no repositório indicado e lista todas as skills
 disponíveis de forma organizada.
 
-Exemplo:
-  skl list github@rmyndharis/antigravity-skills`,
-	Args: cobra.ExactArgs(1),
+Sem repositório, lista as skills instaladas no projeto (o mesmo que skl tree),
+ou as globais com -g.
+
+Exemplos:
+  skl list github@rmyndharis/antigravity-skills
+  skl list -g`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runList,
 }
 
 func init() {
+	addGlobalFlag(listCmd, false)
 	rootCmd.AddCommand(listCmd)
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		cmd.SilenceUsage = true
+		return runTree(cmd, args)
+	}
+
 	refStr := args[0]
 	ref, err := parser.ParseRepo(refStr)
 	if err != nil {
@@ -44,7 +54,7 @@ func runList(cmd *cobra.Command, args []string) error {
 	cat, err := catalog.Fetch(prov, ref.User, ref.Repo, ref.Tag)
 	if err != nil {
 		// Fallback: Try to discover skills by listing directories
-		cloneURL := prov.CloneURL(ref.User, ref.Repo)
+		cloneURL := provider.CloneURLFor(prov, ref.User, ref.Repo)
 		discovered, dErr := installer.DiscoverRemoteSkills(cloneURL, ref.Tag)
 		if dErr != nil || len(discovered) == 0 {
 			repoURL := prov.RepoURL(ref.User, ref.Repo)
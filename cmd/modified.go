@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rduarte/skl/internal/installer"
+	"github.com/rduarte/skl/internal/manifest"
+)
+
+// backupsDir keeps local copies of modified skills replaced by update,
+// relative to the project.
+const backupsDir = ".agent/backups"
+
+// What to do with a skill that was edited since it was installed.
+const (
+	modifiedKeep      = "keep"      // skip the update, leave the edits alone
+	modifiedOverwrite = "overwrite" // discard the edits
+	modifiedBackup    = "backup"    // copy the skill to .agent/backups/<skill>.orig first
+	modifiedPatch     = "patch"     // save the edits as .agent/backups/<skill>.patch first
+)
+
+// findModified returns the sources whose installed copy no longer matches
+// the digest recorded in the lock.
+func findModified(sources []string, locked *manifest.Manifest) map[string]bool {
+	modified := make(map[string]bool)
+	for _, source := range sources {
+		sum := locked.Integrity[source]
+		if sum == nil {
+			continue
+		}
+		state, _, err := checkSkill(source, locked.Skills[source], sum)
+		if err == nil && state == skillModified {
+			modified[source] = true
+		}
+	}
+	return modified
+}
+
+// protectModified decides, for each upgrade of a modified skill, whether to
+// keep the local copy or replace it (optionally saving it first). It returns
+// the upgrades to carry out and the sources that were kept.
+func protectModified(toUpgrade []string, modified map[string]bool, locked *manifest.Manifest, action string) (upgrade, kept []string, err error) {
+	for _, source := range toUpgrade {
+		if !modified[source] {
+			upgrade = append(upgrade, source)
+			continue
+		}
+
+		entry := locked.Skills[source]
+		skill := entry.DirName(source)
+
+		choice := action
+		if choice == "" {
+			choice, err = askModified(skill)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		switch choice {
+		case modifiedKeep:
+			fmt.Printf("⏭️  Mantendo %q: modificada localmente (veja skl diff %s)\n", skill, skill)
+			kept = append(kept, source)
+			continue
+		case modifiedBackup:
+			dest, err := backupSkill(source, entry)
+			if err != nil {
+				return nil, nil, err
+			}
+			fmt.Printf("💾 Cópia local de %q salva em %s\n", skill, dest)
+		case modifiedPatch:
+			dest, err := saveLocalPatch(source, entry)
+			if err != nil {
+				return nil, nil, err
+			}
+			fmt.Printf("💾 Alterações locais de %q salvas em %s\n", skill, dest)
+		}
+		upgrade = append(upgrade, source)
+	}
+	return upgrade, kept, nil
+}
+
+// askModified asks what to do with a modified skill. Without a terminal,
+// the local copy is kept.
+func askModified(skill string) (string, error) {
+	answer, err := ask(fmt.Sprintf("%q foi modificada localmente. [m]anter, [s]obrescrever, [b]ackup (.orig) ou [p]atch e sobrescrever? [M/s/b/p]", skill))
+	if err == errNoTerminal {
+		return modifiedKeep, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	switch answer {
+	case "s", "sobrescrever":
+		return modifiedOverwrite, nil
+	case "b", "backup":
+		return modifiedBackup, nil
+	case "p", "patch":
+		return modifiedPatch, nil
+	}
+	return modifiedKeep, nil
+}
+
+// backupSkill copies an installed skill to .agent/backups/<skill>.orig and
+// returns the destination relative to the project.
+func backupSkill(source string, entry manifest.Entry) (string, error) {
+	dir, err := installedPath(source, entry)
+	if err != nil {
+		return "", err
+	}
+
+	rel := filepath.Join(backupsDir, entry.DirName(source)+".orig")
+	if err := installer.Copy(dir, rel); err != nil {
+		return "", err
+	}
+	return rel, nil
+}
+
+// saveLocalPatch writes the local edits of a skill, as a diff against its
+// locked revision, to .agent/backups/<skill>.patch and returns that path.
+// The patch applies inside the skill directory (git apply --directory).
+func saveLocalPatch(source string, entry manifest.Entry) (string, error) {
+	diff, err := localDiff(source, entry, "--binary")
+	if err != nil {
+		return "", err
+	}
+
+	rel := filepath.Join(backupsDir, entry.DirName(source)+".patch")
+	if err := os.MkdirAll(backupsDir, 0o755); err != nil {
+		return "", fmt.Errorf("erro ao criar %s: %w", backupsDir, err)
+	}
+	if err := os.WriteFile(rel, []byte(diff), 0o644); err != nil {
+		return "", fmt.Errorf("erro ao gravar %s: %w", rel, err)
+	}
+	return rel, nil
+}
+
+// validateModifiedAction checks the value of --on-modified.
+func validateModifiedAction(action string) error {
+	switch action {
+	case "", modifiedKeep, modifiedOverwrite, modifiedBackup, modifiedPatch:
+		return nil
+	}
+	return fmt.Errorf("valor inválido para --on-modified: %q (use keep, overwrite, backup ou patch)", action)
+}
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rduarte/skl/internal/cache"
+	"github.com/rduarte/skl/internal/installer"
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/rduarte/skl/internal/parser"
+	"github.com/rduarte/skl/internal/semver"
+	"github.com/spf13/cobra"
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "Lista as skills com atualizações disponíveis",
+	Long: `Compara o sklfile.lock com os repositórios remotos, sem alterar o projeto.
+
+Para cada skill do sklfile.json mostra:
+  • o commit (e a tag) registrado no sklfile.lock
+  • o commit para o qual a versão do sklfile.json aponta agora
+  • a tag mais recente do repositório, para skills fixadas por tag ou faixa semver
+
+Uma skill está desatualizada quando o sklfile.lock difere da versão mais
+recente dentro da faixa do sklfile.json (o 'skl update' a atualiza). Nesse caso
+o comando termina com código de saída diferente de zero, permitindo usá-lo
+como verificação no CI. Tags mais recentes fora da faixa são apenas
+informadas: para usá-las é preciso alterar a versão no sklfile.json.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runOutdated,
+}
+
+var (
+	outdatedJSON      bool
+	outdatedJobs      int
+	outdatedChangelog bool
+)
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+	outdatedCmd.Flags().BoolVar(&outdatedJSON, "json", false, "Exibe o resultado em JSON")
+	outdatedCmd.Flags().IntVarP(&outdatedJobs, "jobs", "j", 4, "Número máximo de repositórios consultados em paralelo")
+	outdatedCmd.Flags().BoolVar(&outdatedChangelog, "changelog", false, "Lista os commits entre a versão do lock e a remota")
+}
+
+// outdatedStatus is the state of a skill compared with its upstream.
+type outdatedStatus struct {
+	Skill     string `json:"skill"`
+	Source    string `json:"source"`
+	Ref       string `json:"ref"`                  // version in sklfile.json
+	Locked    string `json:"locked,omitempty"`     // commit in sklfile.lock
+	LockedTag string `json:"locked_tag,omitempty"` // tag in sklfile.lock
+	Wanted    string `json:"wanted,omitempty"`     // commit the ref resolves to now
+	WantedTag string `json:"wanted_tag,omitempty"` // tag the ref resolves to now
+	Latest    string `json:"latest,omitempty"`     // newest tag upstream
+	Outdated  bool   `json:"outdated"`
+	Newer     bool   `json:"newer,omitempty"` // Latest is outside of Ref and newer than the lock
+	Error     string `json:"error,omitempty"`
+
+	// Changelog lists the upstream commits between Locked and Wanted
+	// (only with --changelog).
+	Changelog []cache.Commit `json:"changelog,omitempty"`
+	changes   *installer.Changes
+}
+
+func runOutdated(cmd *cobra.Command, args []string) error {
+	mf, err := manifest.Load()
+	if err != nil {
+		return err
+	}
+	lock, err := manifest.LoadLock()
+	if err != nil {
+		return err
+	}
+
+	// Local and filesystem skills have no upstream to compare with
+	var sources []string
+	for _, source := range mf.SortedSources() {
+		if ref, err := parser.Parse(source); err == nil &&
+			(ref.Provider == parser.ProviderLocal || ref.Provider == parser.ProviderFile) {
+			continue
+		}
+		sources = append(sources, source)
+	}
+
+	statuses := make([]outdatedStatus, len(sources))
+	runJobs(outdatedJobs, len(sources), func(i int) {
+		source := sources[i]
+		locked, ok := lock.Skills[source]
+		statuses[i] = checkOutdated(source, mf.Skills[source], locked, ok)
+
+		s := &statuses[i]
+		if outdatedChangelog && s.Error == "" && ok && s.Locked != s.Wanted {
+			target := mf.Skills[source].Locked(s.Wanted, s.WantedTag)
+			if changes, err := skillChanges(source, locked, target); err == nil {
+				s.Changelog, s.changes = changes.Commits, changes
+			}
+		}
+	})
+
+	behind, failed := 0, 0
+	for _, s := range statuses {
+		if s.Outdated {
+			behind++
+		}
+		if s.Error != "" {
+			failed++
+		}
+	}
+
+	if outdatedJSON {
+		if statuses == nil {
+			statuses = []outdatedStatus{}
+		}
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		if len(statuses) == 0 {
+			fmt.Printf("ℹ️  Nenhuma skill remota no %s.\n", manifest.FileName)
+			return nil
+		}
+		printOutdated(statuses)
+		for _, s := range statuses {
+			if s.changes != nil {
+				fmt.Println()
+				printChanges(os.Stdout, s.Skill, s.Locked, s.LockedTag, s.Wanted, s.WantedTag, s.changes)
+			}
+		}
+	}
+
+	if behind > 0 {
+		return fmt.Errorf("%d skill(s) desatualizada(s) — execute 'skl update'", behind)
+	}
+	if failed > 0 {
+		return fmt.Errorf("não foi possível verificar %d skill(s)", failed)
+	}
+	if !outdatedJSON {
+		fmt.Println("\n✅ Todas as skills estão atualizadas")
+	}
+	return nil
+}
+
+// checkOutdated resolves a manifest entry against its remote and compares
+// the result with the lock entry (ok is false when the skill is not locked).
+func checkOutdated(source string, entry, locked manifest.Entry, ok bool) outdatedStatus {
+	s := outdatedStatus{
+		Skill:  entry.DirName(source),
+		Source: source,
+		Ref:    entry.Ref,
+	}
+	if ok {
+		s.Locked = locked.Ref
+		s.LockedTag = locked.Version
+		if s.LockedTag == "" && isVersionTag(entry.Ref) {
+			s.LockedTag = entry.Ref
+		}
+	}
+
+	resolved, err := resolveDesired(source, entry)
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	s.Wanted = resolved.Ref
+	s.WantedTag = resolved.Version
+	if s.WantedTag == "" && isVersionTag(entry.Ref) {
+		s.WantedTag = entry.Ref
+	}
+
+	// Newest tag, only meaningful for skills pinned by tag or range
+	if semver.IsRange(entry.Ref) || isVersionTag(entry.Ref) {
+		if ref, err := parser.Parse(source); err == nil {
+			if cloneURL, _, err := remoteURLs(ref); err == nil {
+				if tags, err := installer.ListTags(cloneURL); err == nil {
+					s.Latest = latestTag(tags)
+				}
+			}
+		}
+	}
+
+	// Only the newest version within the ref counts: a newer tag outside of
+	// it needs the manifest to change, 'skl update' would not install it
+	s.Outdated = !ok || s.Locked != s.Wanted
+	s.Newer = ok && newerTag(s.Latest, s.LockedTag) && newerTag(s.Latest, s.WantedTag)
+	return s
+}
+
+// printOutdated renders the statuses as a table.
+func printOutdated(statuses []outdatedStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SKILL\tVERSÃO\tNO LOCK\tREMOTO\tMAIS RECENTE\tSTATUS")
+	fmt.Fprintln(w, "-----\t------\t-------\t------\t------------\t------")
+	for _, s := range statuses {
+		status := "✅ atualizada"
+		switch {
+		case s.Error != "":
+			status = "⚠️  " + s.Error
+		case s.Locked == "":
+			status = "📦 não instalada"
+		case s.Locked != s.Wanted:
+			status = "↑  desatualizada"
+		case s.Newer:
+			// Up to date with its ref, but a newer tag exists outside of it
+			status = "🆕 " + s.Latest + " disponível (altere a versão no " + manifest.FileName + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			s.Skill, s.Ref, commitLabel(s.Locked, s.LockedTag), commitLabel(s.Wanted, s.WantedTag), orDash(s.Latest), status)
+	}
+	w.Flush()
+}
+
+// commitLabel shows a short commit hash, preceded by its tag when known.
+// Symbolic refs (unresolved branches or tags) are shown as they are.
+func commitLabel(hash, tag string) string {
+	if hash == "" {
+		return "-"
+	}
+	if len(hash) == 40 {
+		hash = hash[:7]
+	}
+	if tag != "" {
+		return tag + " (" + hash + ")"
+	}
+	return hash
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// isVersionTag reports whether ref is a plain version tag such as "v1.2.0".
+func isVersionTag(ref string) bool {
+	if semver.IsRange(ref) {
+		return false
+	}
+	_, err := semver.Parse(ref)
+	return err == nil
+}
+
+// latestTag returns the highest release tag, ignoring pre-releases.
+func latestTag(tags map[string]string) string {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		if v, err := semver.Parse(name); err == nil && v.Pre == "" {
+			names = append(names, name)
+		}
+	}
+	sorted := semver.Sort(names)
+	if len(sorted) == 0 {
+		return ""
+	}
+	return sorted[len(sorted)-1]
+}
+
+// newerTag reports whether latest is a higher version than current.
+func newerTag(latest, current string) bool {
+	lv, err := semver.Parse(latest)
+	if err != nil {
+		return false
+	}
+	cv, err := semver.Parse(current)
+	if err != nil {
+		return false
+	}
+	return lv.Compare(cv) > 0
+}
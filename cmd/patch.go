@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+// patchesDir holds the project's patch overlays, relative to the project.
+const patchesDir = ".agent/patches"
+
+var patchCmd = &cobra.Command{
+	Use:   "patch <skill-name>",
+	Short: "Grava as alterações locais de uma skill como patch reaplicado a cada instalação",
+	Long: `Captura as alterações feitas na cópia instalada de uma skill (o mesmo diff
+exibido por skl diff) em .agent/patches/<skill>.patch e registra o patch no
+sklfile.json. Depois de cada install ou update, o patch é aplicado sobre a
+versão baixada; se ele não se aplicar mais, a instalação falha e a versão
+anterior é mantida.
+
+Execute novamente após novas alterações para atualizar o patch, ou use
+--remove para voltar à versão original no próximo skl update.
+
+Exemplos:
+  skl patch data-analyzer
+  skl patch data-analyzer --remove`,
+	Args:              cobra.ExactArgs(1),
+	SilenceUsage:      true,
+	ValidArgsFunction: completeRemoteSkills,
+	RunE:              runPatch,
+}
+
+var removePatch bool
+
+func init() {
+	rootCmd.AddCommand(patchCmd)
+	patchCmd.Flags().BoolVar(&removePatch, "remove", false, "Remove o patch da skill")
+}
+
+func runPatch(cmd *cobra.Command, args []string) error {
+	skill := args[0]
+
+	mf, err := manifest.Load()
+	if err != nil {
+		return fmt.Errorf("erro ao carregar %s: %w", manifest.FileName, err)
+	}
+	source, err := findSkill(mf, skill)
+	if err != nil {
+		return err
+	}
+	if source == "" {
+		return fmt.Errorf("skill %q não encontrada no %s", skill, manifest.FileName)
+	}
+	entry := mf.Skills[source]
+	skill = entry.DirName(source)
+
+	if removePatch {
+		return dropPatch(mf, source, entry)
+	}
+
+	lock, err := manifest.LoadLock()
+	if err != nil {
+		return fmt.Errorf("erro ao carregar %s: %w", manifest.LockFileName, err)
+	}
+	locked, ok := lock.Skills[source]
+	if !ok {
+		return fmt.Errorf("skill %q não está instalada (execute skl update)", skill)
+	}
+
+	// The patch is taken against the exact upstream revision installed
+	diff, err := localDiff(source, locked, "--binary")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return fmt.Errorf("skill %q não tem alterações locais em relação a %s", skill, commitLabel(locked.Ref, locked.Version))
+	}
+
+	rel := filepath.ToSlash(filepath.Join(patchesDir, skill+".patch"))
+	if err := os.MkdirAll(patchesDir, 0o755); err != nil {
+		return fmt.Errorf("erro ao criar %s: %w", patchesDir, err)
+	}
+	if err := os.WriteFile(rel, []byte(diff), 0o644); err != nil {
+		return fmt.Errorf("erro ao gravar %s: %w", rel, err)
+	}
+	fmt.Printf("🩹 Patch salvo em %s\n", rel)
+
+	entry.Patch = rel
+	if err := mf.Add(source, entry); err != nil {
+		return fmt.Errorf("erro ao registrar patch no %s: %w", manifest.FileName, err)
+	}
+
+	// The installed copy already has the changes: record it as installed,
+	// so update and verify don't see a difference
+	locked.Patch = rel
+	lock.Skills[source] = locked
+	if err := recordIntegrity(lock, source); err != nil {
+		return err
+	}
+	if err := lock.SaveLock(); err != nil {
+		return fmt.Errorf("erro ao atualizar %s: %w", manifest.LockFileName, err)
+	}
+
+	fmt.Printf("📋 Patch registrado no %s — será reaplicado após cada install/update\n", manifest.FileName)
+	return nil
+}
+
+// dropPatch deletes the patch of a skill and unregisters it. The installed
+// copy is left as is until the next update reinstalls the original.
+func dropPatch(mf *manifest.Manifest, source string, entry manifest.Entry) error {
+	skill := entry.DirName(source)
+	if entry.Patch == "" {
+		return fmt.Errorf("skill %q não tem patch registrado", skill)
+	}
+
+	if err := os.Remove(entry.Patch); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("erro ao remover %s: %w", entry.Patch, err)
+	}
+	fmt.Printf("🗑️  Patch removido: %s\n", entry.Patch)
+
+	entry.Patch = ""
+	if err := mf.Add(source, entry); err != nil {
+		return fmt.Errorf("erro ao atualizar %s: %w", manifest.FileName, err)
+	}
+
+	fmt.Printf("📋 Execute 'skl update' para reinstalar a versão original de %q\n", skill)
+	return nil
+}
@@ -13,10 +13,14 @@ import (
 var removeCmd = &cobra.Command{
 	Use:   "remove <skill-name>",
 	Short: "Remove uma skill instalada",
-	Long: `Remove uma skill do diretório .agent/skills/ e do sklfile.json.
-
-Exemplo:
-  skl remove 1doc-api-expert`,
+	Long: `Remove uma skill do diretório .agent/skills/ (ou do diretório configurado
+em "target") e do sklfile.json. Skills instaladas com "as" são removidas
+pelo nome do alias. Quando mais de uma skill tem o mesmo nome, informe a
+fonte completa.
+
+Exemplos:
+  skl remove 1doc-api-expert
+  skl remove github@empresa/repo-skills/code-reviewer`,
 	Args: cobra.ExactArgs(1),
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) != 0 {
@@ -28,9 +32,13 @@ Exemplo:
 			return nil, cobra.ShellCompDirectiveError
 		}
 
+		// Names shared by several skills are completed with the full source
 		var suggestions []string
-		for source := range lock.Skills {
-			name := manifest.SkillName(source)
+		for source, entry := range lock.Skills {
+			name := entry.DirName(source)
+			if len(lock.FindAll(name)) > 1 {
+				name = source
+			}
 			if strings.HasPrefix(name, toComplete) {
 				suggestions = append(suggestions, name)
 			}
@@ -42,52 +50,73 @@ Exemplo:
 }
 
 func init() {
+	addGlobalFlag(removeCmd, true)
 	rootCmd.AddCommand(removeCmd)
 }
 
 func runRemove(cmd *cobra.Command, args []string) error {
 	skill := args[0]
 
-	cwd, err := os.Getwd()
+	mf, err := manifest.Load()
+	if err != nil {
+		return fmt.Errorf("erro ao carregar %s: %w", manifest.FileName, err)
+	}
+	lock, err := manifest.LoadLock()
 	if err != nil {
-		return fmt.Errorf("erro ao obter diretório atual: %w", err)
+		return fmt.Errorf("erro ao carregar %s: %w", manifest.LockFileName, err)
 	}
 
-	skillDir := filepath.Join(cwd, ".agent", "skills", skill)
+	// Find the key installed under <skill> (its alias or skill name, or the
+	// full source when several skills share the name), so an entry with a
+	// custom target is removed from the right directory
+	// Installed skills (lock) and skills not installed yet (manifest) are
+	// looked up together, so a shared name is never resolved to the wrong one
+	known := &manifest.Manifest{Skills: make(map[string]manifest.Entry)}
+	for source, entry := range lock.Skills {
+		known.Skills[source] = entry
+	}
+	for source, entry := range mf.Skills {
+		known.Skills[source] = entry
+	}
+	source, err := findSkill(known, skill)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	entry, ok := known.Skills[source]
+	if ok {
+		skill = entry.DirName(source)
+	} else if strings.ContainsAny(skill, "@/") {
+		return fmt.Errorf("skill %q não encontrada (nem instalada, nem no %s)", skill, manifest.FileName)
+	}
+	matchedKey := ""
+	if _, listed := mf.Skills[source]; listed {
+		matchedKey = source
+	}
 	removed := false
 
-	// 1. Remove skill directory if it exists
-	if _, err := os.Stat(skillDir); err == nil {
-		if err := os.RemoveAll(skillDir); err != nil {
+	// 1. Remove skill directories (and copies in other targets) if they exist
+	for _, dir := range skillsDirs(entry) {
+		relDir := filepath.ToSlash(filepath.Join(dir, skill))
+		if _, err := os.Stat(relDir); err != nil {
+			continue
+		}
+		if err := os.RemoveAll(relDir); err != nil {
 			return fmt.Errorf("erro ao remover diretório: %w", err)
 		}
-		fmt.Printf("🗑️  Diretório removido: .agent/skills/%s\n", skill)
+		fmt.Printf("🗑️  Diretório removido: %s\n", relDir)
 		removed = true
 	}
 
 	// 2. Remove from sklfile.json if listed
-	mf, err := manifest.Load()
-	if err != nil {
-		return fmt.Errorf("erro ao carregar %s: %w", manifest.FileName, err)
-	}
-
-	// Find the key that ends with /<skill>
-	var matchedKey string
-	for source := range mf.Skills {
-		parts := strings.Split(source, "/")
-		if len(parts) > 0 && parts[len(parts)-1] == skill {
-			matchedKey = source
-			break
-		}
-	}
-
 	if matchedKey != "" {
 		delete(mf.Skills, matchedKey)
 		if err := mf.Save(); err != nil {
 			return fmt.Errorf("erro ao atualizar %s: %w", manifest.FileName, err)
 		}
-		// Update lock file
-		if err := mf.SaveLock(); err != nil {
+		// Update lock file, keeping the entries of the other skills
+		lock.Remove(matchedKey)
+		if err := lock.SaveLock(); err != nil {
 			return fmt.Errorf("erro ao atualizar %s: %w", manifest.LockFileName, err)
 		}
 		fmt.Printf("📋 Removida do %s e %s: %s\n", manifest.FileName, manifest.LockFileName, matchedKey)
@@ -99,5 +128,6 @@ func runRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("✅ Skill %q removida\n", skill)
+	refreshIndex(mf.Index)
 	return nil
 }
@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rduarte/skl/internal/deps"
+	"github.com/rduarte/skl/internal/installer"
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/rduarte/skl/internal/parser"
+)
+
+// requirement is a dependency on a skill, as declared by another one.
+type requirement struct {
+	from string // source of the skill that requires it
+	ref  string // version asked for ("*" for any, "" for the revision of from)
+}
+
+// label describes the version asked for, for messages.
+func (r requirement) label() string {
+	if r.ref == "" {
+		return "(mesma revisão de " + manifest.SkillName(r.from) + ")"
+	}
+	return r.ref
+}
+
+// resolveRequires walks the dependencies of roots (declared in SKILL.md or
+// skill.json) and adds the missing ones to resolved as indirect entries,
+// recording the dependencies of every visited skill in resolved.Requires.
+//
+// Skills already in resolved are kept as they are: a direct entry of
+// sklfile.json always wins over what its dependents ask for. Two skills
+// asking for different revisions of the same indirect dependency is a
+// conflict, and so is a dependency cycle.
+//
+// When the dependencies of a skill cannot be read, the ones recorded in
+// resolved.Requires are kept, with their entries taken from previous (the
+// current lock), so an unreachable repository never uninstalls them.
+func resolveRequires(resolved, previous *manifest.Manifest, roots []string) (added []string, err error) {
+	wanted := make(map[string]requirement)
+	visited := make(map[string]bool)
+	queue := append([]string{}, roots...)
+
+	for len(queue) > 0 {
+		source := queue[0]
+		queue = queue[1:]
+		if visited[source] {
+			continue
+		}
+		visited[source] = true
+
+		reqs, err := skillRequires(source, resolved.Skills[source])
+		if err != nil {
+			// Keep what was known about it, the next run will retry
+			fmt.Printf("⚠️  Não foi possível ler as dependências de %q: %v\n", source, err)
+			for _, dep := range resolved.Requires[source] {
+				if _, ok := resolved.Skills[dep]; !ok {
+					if prev, ok := previous.Skills[dep]; ok {
+						resolved.Skills[dep] = prev
+					}
+				}
+				queue = append(queue, dep)
+			}
+			continue
+		}
+
+		var requires []string
+		for _, req := range reqs {
+			dep, ref, err := deps.Resolve(req, source)
+			if err != nil {
+				return added, fmt.Errorf("%s: %w", source, err)
+			}
+			if dep == source {
+				return added, fmt.Errorf("%s: a skill não pode depender de si mesma", source)
+			}
+
+			isNew, err := addRequirement(resolved, previous, wanted, requirement{from: source, ref: ref}, dep)
+			if err != nil {
+				return added, err
+			}
+			if isNew {
+				added = append(added, dep)
+			}
+			requires = append(requires, dep)
+			queue = append(queue, dep)
+		}
+		resolved.SetRequires(source, requires)
+	}
+
+	if cycle := deps.FindCycle(resolved.Requires); cycle != nil {
+		names := make([]string, len(cycle))
+		for i, source := range cycle {
+			names[i] = manifest.SkillName(source)
+		}
+		return added, fmt.Errorf("dependência circular entre skills: %s", strings.Join(names, " → "))
+	}
+
+	sort.Strings(added)
+	return added, nil
+}
+
+// addRequirement records that req.from requires dep and, when dep is not in
+// resolved yet, resolves it and adds it as an indirect entry (reporting
+// isNew). Entries resolved before this walk (direct ones or those already
+// installed) are left alone; a mismatch with what req asks for is only
+// reported. A dependency of previous that cannot be resolved now keeps its
+// entry there.
+func addRequirement(resolved, previous *manifest.Manifest, wanted map[string]requirement, req requirement, dep string) (isNew bool, err error) {
+	current, exists := resolved.Skills[dep]
+	first, resolvedHere := wanted[dep]
+
+	if exists && !resolvedHere {
+		if req.ref == "*" {
+			return false, nil
+		}
+		want, err := resolveRequirement(resolved, req, dep)
+		if err == nil && want.Ref != current.Ref {
+			fmt.Printf("⚠️  %q requer %s %s → %s, mas %s está em %s — versão mantida\n",
+				manifest.SkillName(req.from), manifest.SkillName(dep), req.label(),
+				commitLabel(want.Ref, want.Version), current.DirName(dep), commitLabel(current.Ref, current.Version))
+		}
+		return false, nil
+	}
+
+	want, err := resolveRequirement(resolved, req, dep)
+	if err != nil {
+		if prev, ok := previous.Skills[dep]; ok && !resolvedHere {
+			fmt.Printf("⚠️  Não foi possível resolver a dependência %q: %v — versão instalada mantida\n", dep, err)
+			resolved.Skills[dep] = prev
+			return false, nil
+		}
+		return false, fmt.Errorf("dependência %q de %q: %w", dep, manifest.SkillName(req.from), err)
+	}
+
+	if resolvedHere {
+		if want.Ref != current.Ref {
+			return false, fmt.Errorf(
+				"conflito de versões para %q:\n"+
+					"  %q requer %s → %s\n"+
+					"  %q requer %s → %s\n\n"+
+					"  Adicione a skill ao %s para escolher a versão",
+				dep,
+				manifest.SkillName(first.from), first.label(), commitLabel(current.Ref, current.Version),
+				manifest.SkillName(req.from), req.label(), commitLabel(want.Ref, want.Version),
+				manifest.FileName,
+			)
+		}
+		return false, nil
+	}
+
+	want.Indirect = true
+	resolved.Skills[dep] = want
+	wanted[dep] = req
+	return true, nil
+}
+
+// resolveRequirement returns the lock entry for what req asks of dep. A
+// skill of the same repository without a tag follows the revision of the
+// skill requiring it.
+func resolveRequirement(resolved *manifest.Manifest, req requirement, dep string) (manifest.Entry, error) {
+	if req.ref == "" {
+		from := resolved.Skills[req.from]
+		return manifest.Entry{}.Locked(from.Ref, from.Version), nil
+	}
+	return resolveDesired(dep, manifest.Entry{Ref: req.ref})
+}
+
+// skillRequires reads the dependencies a skill declares at the revision of
+// its (resolved) entry. Local and filesystem skills are read from disk.
+func skillRequires(source string, entry manifest.Entry) ([]string, error) {
+	ref, err := parser.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	switch ref.Provider {
+	case parser.ProviderLocal:
+		dir, err := installedPath(source, entry)
+		if err != nil {
+			return nil, err
+		}
+		return deps.FromDir(dir)
+	case parser.ProviderFile:
+		return deps.FromDir(ref.Location)
+	}
+
+	cloneURL, repoURL, err := remoteURLs(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Ref != "*" {
+		ref.Tag = entry.Ref
+	}
+	overridePath := entry.Path
+	if overridePath == "" {
+		overridePath = skillRepoPath(ref)
+	}
+	return installer.FetchRequires(cloneURL, repoURL, ref.Skill, ref.Tag, overridePath)
+}
+
+// requiredBy returns the skills of m that depend on source.
+func requiredBy(m *manifest.Manifest, source string) []string {
+	var dependents []string
+	for _, from := range m.SortedSources() {
+		for _, dep := range m.Requires[from] {
+			if dep == source {
+				dependents = append(dependents, from)
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+// installDependencies installs the skills that source requires and are not
+// in lock yet, recording them as indirect entries. Dependencies that fail
+// to install are left out of the lock, so the next update retries them.
+func installDependencies(lock *manifest.Manifest, source string) error {
+	added, err := resolveRequires(lock, lock, []string{source})
+	if err == nil {
+		err = checkCollisions(lock)
+	}
+	if err != nil {
+		for _, dep := range added {
+			lock.Remove(dep)
+		}
+		return err
+	}
+
+	var failed []string
+	for _, dep := range added {
+		entry := lock.Skills[dep]
+		install := entry
+		if entry.Version != "" {
+			install.Ref = entry.Version
+		}
+
+		fmt.Printf("\n📦 Instalando dependência %q (requerida por %s)...\n", entry.DirName(dep), dependentNames(lock, dep))
+		commit, err := installSkill(os.Stdout, dep, install)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", entry.DirName(dep), err)
+			lock.Remove(dep)
+			failed = append(failed, entry.DirName(dep))
+			continue
+		}
+		if commit != "" {
+			entry.Ref = commit
+			lock.Skills[dep] = entry
+		}
+		if err := recordIntegrity(lock, dep); err != nil {
+			fmt.Printf("⚠️  Aviso: Não foi possível registrar a integridade da skill: %v\n", err)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("não foi possível instalar %d dependência(s): %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// dependentNames lists the names of the skills requiring source.
+func dependentNames(m *manifest.Manifest, source string) string {
+	var names []string
+	for _, from := range requiredBy(m, source) {
+		names = append(names, fmt.Sprintf("%q", m.Skills[from].DirName(from)))
+	}
+	return strings.Join(names, ", ")
+}
@@ -2,7 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/rduarte/skl/internal/provider"
 	"github.com/rduarte/skl/internal/updater"
 	"github.com/spf13/cobra"
 )
@@ -17,20 +22,36 @@ var rootCmd = &cobra.Command{
 "skills" (capacidades/ferramentas de IA) dentro de projetos locais.
 
 Ele faz o download de skills armazenadas em repositórios Git
-(GitHub, Bitbucket) e as organiza no diretório .agent/skills/.`,
+(GitHub, Bitbucket, GitLab) e as organiza no diretório .agent/skills/.
+
+Os comandos podem ser executados de qualquer subdiretório: o projeto é o
+diretório mais próximo, subindo a partir do atual, que tem um sklfile.json
+(ou, sem ele, a raiz do repositório Git).`,
 	Version: Version,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if projectErr != nil {
+			return projectErr
+		}
+		if transportErr != nil {
+			return transportErr
+		}
+
 		// Skip version check for specific commands
 		skipped := []string{"upgrade", "completion", "help", "setup"}
 		for _, s := range skipped {
 			if cmd.Name() == s || (cmd.Parent() != nil && cmd.Parent().Name() == s) {
-				return
+				return nil
 			}
 		}
 
+		// Keep machine-readable output clean
+		if f := cmd.Flags().Lookup("json"); f != nil && f.Value.String() == "true" {
+			return nil
+		}
+
 		// Skip if Version is "dev"
 		if Version == "dev" {
-			return
+			return nil
 		}
 
 		// Perform check (ignore errors to not block user)
@@ -39,6 +60,7 @@ Ele faz o download de skills armazenadas em repositórios Git
 			fmt.Printf("\033[1;33m⚠  Nova versão do skl disponível: %s (atual: %s)\033[0m\n", latest, Version)
 			fmt.Printf("\033[1;33m   Execute 'skl upgrade' para atualizar.\033[0m\n\n")
 		}
+		return nil
 	},
 }
 
@@ -49,4 +71,89 @@ func Execute() error {
 
 func init() {
 	rootCmd.SetVersionTemplate(fmt.Sprintf("skl version %s\n", Version))
+	rootCmd.PersistentFlags().StringVar(&transportFlag, "transport", "", "Transporte usado para clonar: ssh ou https (padrão: $SKL_TRANSPORT ou automático)")
+	rootCmd.PersistentFlags().StringVarP(&dirFlag, "dir", "C", "", "Executa como se o skl tivesse sido iniciado em <dir>")
+	cobra.OnInitialize(loadProviders, enterProject)
+}
+
+var transportFlag string
+
+var dirFlag string
+
+// invocationDir is the directory skl was started in (or -C), before
+// moving to the project root. projectErr is reported before the command
+// runs (completions run without it).
+var (
+	invocationDir string
+	projectErr    error
+)
+
+// enterProject moves the process to the project root (see
+// manifest.FindRoot), so every command reads and writes sklfile.json and
+// the skills there, whatever subdirectory it was run from.
+func enterProject() {
+	if dirFlag != "" {
+		if err := os.Chdir(dirFlag); err != nil {
+			projectErr = fmt.Errorf("-C %s: %w", dirFlag, err)
+			return
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		projectErr = fmt.Errorf("erro ao obter diretório atual: %w", err)
+		return
+	}
+	invocationDir = cwd
+
+	if root := manifest.FindRoot(cwd); root != cwd {
+		if err := os.Chdir(root); err != nil {
+			projectErr = err
+		}
+	}
+}
+
+// fromInvocationDir rewrites a file: reference given on the command line,
+// relative to the directory skl was started in, to be relative to the
+// current directory (the project root). Global skills keep an absolute path.
+func fromInvocationDir(raw string) string {
+	location, ok := strings.CutPrefix(raw, "file:")
+	if !ok || filepath.IsAbs(location) || invocationDir == "" {
+		return raw
+	}
+
+	abs := filepath.Join(invocationDir, location)
+	if globalMode {
+		return "file:" + abs
+	}
+	cwd, err := os.Getwd()
+	if err != nil || cwd == invocationDir {
+		return raw
+	}
+	rel, err := filepath.Rel(cwd, abs)
+	if err != nil {
+		return raw
+	}
+	return "file:" + filepath.ToSlash(rel)
+}
+
+// transportErr is an invalid --transport or SKL_TRANSPORT, reported before
+// the command runs: a transport asked for explicitly is never ignored.
+var transportErr error
+
+// loadProviders registers the custom providers declared in providers.json
+// and applies the transport selected via --transport or SKL_TRANSPORT.
+// providers.json errors are reported but never block the command.
+func loadProviders() {
+	if err := provider.LoadConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠  Providers personalizados ignorados: %v\n", err)
+	}
+
+	t, origin := transportFlag, "--transport"
+	if t == "" {
+		t, origin = os.Getenv("SKL_TRANSPORT"), "SKL_TRANSPORT"
+	}
+	if err := provider.SetTransport(t); err != nil {
+		transportErr = fmt.Errorf("%s: %w", origin, err)
+	}
 }
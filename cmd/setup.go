@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/rduarte/skl/internal/installer"
 	"github.com/rduarte/skl/internal/manifest"
@@ -11,8 +13,8 @@ import (
 var setupCmd = &cobra.Command{
 	Use:   "setup",
 	Short: "Indexa folders locais em .agent/skills como skills gerenciadas",
-	Long: `Verifica subdiretórios em .agent/skills/ que não estão no manifesto 
-e os adiciona como skills locais (local@nome-da-skill).`,
+	Long: `Verifica subdiretórios em .agent/skills/ (ou nos "targets" do sklfile.json)
+que não estão no manifesto e os adiciona como skills locais (local@nome-da-skill).`,
 	RunE: runSetup,
 }
 
@@ -21,44 +23,72 @@ func init() {
 }
 
 func runSetup(cmd *cobra.Command, args []string) error {
-	fmt.Println("🔍 Buscando skills locais não indexadas em .agent/skills/...")
+	dirs := skillsDirs(manifest.Entry{})
+	fmt.Printf("🔍 Buscando skills locais não indexadas em %s...\n", strings.Join(dirs, ", "))
 
-	// 1. Get folder names from .agent/skills
-	folders, err := installer.List()
+	// 1. Load current manifesto
+	mf, err := manifest.Load()
 	if err != nil {
-		return fmt.Errorf("erro ao listar diretório de skills: %w", err)
-	}
-
-	if len(folders) == 0 {
-		fmt.Println("✅ Nenhuma pasta encontrada em .agent/skills/.")
-		return nil
+		return fmt.Errorf("erro ao carregar %s: %w", manifest.FileName, err)
 	}
 
-	// 2. Load current manifesto
-	mf, err := manifest.Load()
+	lock, err := manifest.LoadLock()
 	if err != nil {
-		return fmt.Errorf("erro ao carregar %s: %w", manifest.FileName, err)
+		return fmt.Errorf("erro ao carregar %s: %w", manifest.LockFileName, err)
 	}
 
-	// 3. Map tracked skills to their folder names
+	// 2. Map tracked skills (and their copies) to their directories
 	tracked := make(map[string]bool)
-	for source := range mf.Skills {
-		tracked[manifest.SkillName(source)] = true
+	for _, source := range allSources(mf, lock) {
+		entry, ok := mf.Skills[source]
+		if !ok {
+			entry = lock.Skills[source]
+		}
+		for _, path := range skillPaths(source, entry) {
+			tracked[path] = true
+		}
 	}
 
-	addedCount := 0
-	for _, folder := range folders {
-		if !tracked[folder] {
-			// Add as local@folder
+	// 3. Index the folders of every target directory; those outside the
+	// first one keep their directory as target
+	found, addedCount := 0, 0
+	for i, dir := range dirs {
+		folders, err := installer.List(dir)
+		if err != nil {
+			return fmt.Errorf("erro ao listar diretório de skills: %w", err)
+		}
+		found += len(folders)
+
+		for _, folder := range folders {
+			if tracked[filepath.ToSlash(filepath.Join(dir, folder))] {
+				continue
+			}
 			source := "local@" + folder
+			if _, exists := mf.Skills[source]; exists {
+				fmt.Printf("⚠️  %s ignorada: já existe uma skill %q\n", filepath.Join(dir, folder), source)
+				continue
+			}
+
+			// Add as local@folder
+			entry := manifest.Entry{Ref: "*"}
+			if i > 0 {
+				entry.Target = dir
+			}
 			fmt.Printf("➕ Indexando skill local: %q\n", folder)
-			mf.Skills[source] = "*"
+			mf.Skills[source] = entry
+			lock.Skills[source] = entry
 			addedCount++
 		}
 	}
 
+	if found == 0 {
+		fmt.Printf("✅ Nenhuma pasta encontrada em %s.\n", strings.Join(dirs, ", "))
+		return nil
+	}
+
 	if addedCount == 0 {
 		fmt.Println("✅ Todas as skills locais já estão indexadas no manifesto.")
+		refreshIndex(mf.Index)
 		return nil
 	}
 
@@ -66,7 +96,7 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	if err := mf.Save(); err != nil {
 		return err
 	}
-	if err := mf.SaveLock(); err != nil {
+	if err := lock.SaveLock(); err != nil {
 		return err
 	}
 
@@ -75,5 +105,6 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	// Ensure lock file is ignored in .gitignore
 	_ = manifest.EnsureIgnoreLock()
 
+	refreshIndex(mf.Index)
 	return nil
 }
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rduarte/skl/internal/catalog"
+	"github.com/rduarte/skl/internal/installer"
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/rduarte/skl/internal/parser"
+	"github.com/rduarte/skl/internal/provider"
+	"github.com/rduarte/skl/internal/semver"
+)
+
+// remoteURLs returns the clone URL and the browsable URL of the repository
+// behind a remote reference (registry providers or git+ sources).
+func remoteURLs(ref *parser.SkillRef) (cloneURL, repoURL string, err error) {
+	switch ref.Provider {
+	case parser.ProviderGit:
+		return ref.Location, provider.Redact(ref.Location), nil
+	case parser.ProviderLocal, parser.ProviderFile:
+		return "", "", fmt.Errorf("a referência %q não aponta para um repositório remoto", ref.Source())
+	}
+
+	prov, err := provider.New(ref.Provider)
+	if err != nil {
+		return "", "", err
+	}
+
+	return provider.CloneURLFor(prov, ref.User, ref.Repo), prov.RepoURL(ref.User, ref.Repo), nil
+}
+
+// resolveTag returns the concrete tag to check out for a ref: semver ranges
+// (e.g. "^1.2") are resolved to the highest matching remote tag, anything
+// else is returned as is.
+func resolveTag(cloneURL, tag string) (string, error) {
+	if !semver.IsRange(tag) {
+		return tag, nil
+	}
+	resolved, _, err := installer.ResolveVersion(cloneURL, tag)
+	return resolved, err
+}
+
+// installedPath returns the absolute directory a manifest entry is installed
+// in, honoring its alias ("as") and target directory. With several targets
+// this is the tracked copy, in the first one.
+func installedPath(source string, entry manifest.Entry) (string, error) {
+	return installer.SkillPath(skillsDirs(entry)[0], entry.DirName(source))
+}
+
+// skillRepoPath returns the explicit in-repo path of a skill: the path from
+// a git+ fragment or the one declared in the repository's catalog.json.
+// An empty result means the installer should look in the default locations.
+func skillRepoPath(ref *parser.SkillRef) string {
+	if ref.Provider == parser.ProviderGit {
+		return ref.Path
+	}
+
+	prov, err := provider.New(ref.Provider)
+	if err != nil {
+		return ""
+	}
+
+	cat, err := catalog.Fetch(prov, ref.User, ref.Repo, ref.Tag)
+	if err == nil && cat != nil {
+		if entry := cat.Find(ref.Skill); entry != nil && entry.Path != "" {
+			return entry.Path
+		}
+	}
+	return ""
+}
+
+// findSkill returns the source of m matching name, a full source or the
+// directory name of a skill ("" when there is none). A name shared by
+// several skills is an error: the full source is needed to pick one.
+func findSkill(m *manifest.Manifest, name string) (string, error) {
+	sources := m.FindAll(name)
+	switch len(sources) {
+	case 0:
+		return "", nil
+	case 1:
+		return sources[0], nil
+	}
+	return "", fmt.Errorf("há %d skills chamadas %q:\n  %s\n\n  Informe a fonte completa (por exemplo, %s)",
+		len(sources), name, strings.Join(sources, "\n  "), sources[0])
+}
+
+// dirOwner returns the skill of mf or lock, other than source, that is
+// installed in one of the directories entry would be installed in.
+func dirOwner(mf, lock *manifest.Manifest, source string, entry manifest.Entry) (owner, dir string) {
+	paths := make(map[string]bool)
+	for _, path := range skillPaths(source, entry) {
+		paths[path] = true
+	}
+	for _, other := range allSources(mf, lock) {
+		if other == source {
+			continue
+		}
+		otherEntry, ok := mf.Skills[other]
+		if !ok {
+			otherEntry = lock.Skills[other]
+		}
+		for _, path := range skillPaths(other, otherEntry) {
+			if paths[path] {
+				return other, path
+			}
+		}
+	}
+	return "", ""
+}
+
+// checkCollisions fails when two skills of m would be installed in the
+// same directory (e.g. two repositories shipping a "code-reviewer" skill).
+func checkCollisions(m *manifest.Manifest) error {
+	owners := make(map[string][]string)
+	var dirs []string
+	for _, source := range m.SortedSources() {
+		for _, dir := range skillPaths(source, m.Skills[source]) {
+			if len(owners[dir]) == 1 {
+				dirs = append(dirs, dir)
+			}
+			owners[dir] = append(owners[dir], source)
+		}
+	}
+	if len(dirs) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString("conflito de nomes: skills diferentes seriam instaladas no mesmo diretório\n")
+	for _, dir := range dirs {
+		fmt.Fprintf(&b, "\n  %s:\n", dir)
+		for _, source := range owners[dir] {
+			fmt.Fprintf(&b, "    %s\n", source)
+		}
+	}
+	fmt.Fprintf(&b, "\n  Defina \"as\" (outro nome de diretório) em uma delas no %s ou reinstale-a com: skl install <fonte> --as <nome>", manifest.FileName)
+	return fmt.Errorf("%s", b.String())
+}
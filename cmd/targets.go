@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/rduarte/skl/internal/installer"
+	"github.com/rduarte/skl/internal/manifest"
+)
+
+// projectTargets returns the "targets" of sklfile.json, read once per run.
+var projectTargets = sync.OnceValue(func() []string {
+	mf, err := manifest.Load()
+	if err != nil {
+		return nil
+	}
+	return mf.Targets
+})
+
+// skillsDirs returns the skills directories an entry is installed in,
+// relative to the project: its own target or the targets of sklfile.json
+// (default .agent/skills). The first one holds the tracked copy.
+func skillsDirs(entry manifest.Entry) []string {
+	return (&manifest.Manifest{Targets: projectTargets()}).SkillsDirs(entry)
+}
+
+// skillPaths returns every directory an entry is installed in, relative to
+// the project and with forward slashes, the tracked copy first.
+func skillPaths(source string, entry manifest.Entry) []string {
+	return pathsIn(skillsDirs(entry), source, entry)
+}
+
+func pathsIn(dirs []string, source string, entry manifest.Entry) []string {
+	paths := make([]string, len(dirs))
+	for i, dir := range dirs {
+		paths[i] = filepath.ToSlash(filepath.Join(dir, entry.DirName(source)))
+	}
+	return paths
+}
+
+// mirrorSkill copies the tracked copy of a skill to its other target
+// directories. With missingOnly, existing copies are left alone.
+func mirrorSkill(source string, entry manifest.Entry, missingOnly bool) error {
+	paths := skillPaths(source, entry)
+	if len(paths) < 2 {
+		return nil
+	}
+
+	src, err := installedPath(source, entry)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(src); err != nil {
+		return nil // not installed, nothing to copy
+	}
+
+	for _, path := range paths[1:] {
+		if missingOnly {
+			if _, err := os.Stat(path); err == nil {
+				continue
+			}
+		}
+		if err := installer.Copy(src, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncTargets brings the copies of every skill in line with the targets of
+// desired after an update: copies are added to new target directories and
+// deleted from the ones no longer used (as recorded in locked). Skills in
+// installed were just copied everywhere and are skipped.
+func syncTargets(desired, locked *manifest.Manifest, installed map[string]bool) []string {
+	var failures []string
+
+	// Drop copies from directories no skill is installed in anymore
+	for _, path := range staleCopies(desired, locked) {
+		if err := os.RemoveAll(path); err != nil {
+			failures = append(failures, fmt.Sprintf("  ✗ %s: %v", path, err))
+			continue
+		}
+		fmt.Printf("🗑️  Cópia removida: %s\n", path)
+	}
+
+	// Fill in the copies missing from the target directories
+	for _, source := range desired.SortedSources() {
+		if installed[source] {
+			continue
+		}
+		if err := mirrorSkill(source, desired.Skills[source], true); err != nil {
+			failures = append(failures, fmt.Sprintf("  ✗ %s: %v", manifest.SkillName(source), err))
+		}
+	}
+	return failures
+}
+
+// staleCopies returns the copies of skills left on disk in directories they
+// are no longer installed in: targets dropped from sklfile.json or changed
+// since locked was written. The directory of a local skill is never one of
+// them, nor the directories a removed skill is deleted from anyway.
+func staleCopies(desired, locked *manifest.Manifest) []string {
+	keep := make(map[string]bool)
+	for source, entry := range desired.Skills {
+		for _, path := range skillPaths(source, entry) {
+			keep[path] = true
+		}
+	}
+
+	var stale []string
+	for _, source := range locked.SortedSources() {
+		prev := locked.Skills[source]
+		old := pathsIn(locked.SkillsDirs(prev), source, prev)
+		local := strings.HasPrefix(source, "local@")
+		if local {
+			old = old[1:]
+		}
+
+		removed := make(map[string]bool)
+		if _, ok := desired.Skills[source]; !ok && !local {
+			for _, path := range skillPaths(source, prev) {
+				removed[path] = true
+			}
+		}
+
+		for _, path := range old {
+			if keep[path] || removed[path] {
+				continue
+			}
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			stale = append(stale, path)
+		}
+	}
+	return stale
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/rduarte/skl/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var treeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "Mostra as skills do projeto agrupadas por repositório",
+	Long: `Lista as skills do sklfile.json e as dependências registradas no
+sklfile.lock, agrupadas pelo repositório de origem, com a versão pedida, o
+commit instalado e as dependências declaradas por cada skill.
+
+As skills globais (skl install -g) aparecem no final, indicando as que uma
+skill do projeto com o mesmo nome sobrepõe.
+
+Lê apenas o sklfile.json e o sklfile.lock: funciona sem acesso à rede.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runTree,
+}
+
+func init() {
+	addGlobalFlag(treeCmd, false)
+	rootCmd.AddCommand(treeCmd)
+}
+
+func runTree(cmd *cobra.Command, args []string) error {
+	mf, err := manifest.Load()
+	if err != nil {
+		return err
+	}
+	lock, err := manifest.LoadLock()
+	if err != nil {
+		return err
+	}
+
+	// Manifest entries plus the indirect ones only found in the lock
+	groups := make(map[string][]string)
+	for _, source := range allSources(mf, lock) {
+		group := repoLabel(source)
+		groups[group] = append(groups[group], source)
+	}
+	if len(groups) == 0 {
+		fmt.Printf("ℹ️  Nenhuma skill no %s.\n", manifest.FileName)
+		printGlobalSkills(lock)
+		return nil
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("📦 %s\n", name)
+
+		sources := groups[name]
+		for j, source := range sources {
+			branch, indent := "├── ", "│   "
+			if j == len(sources)-1 {
+				branch, indent = "└── ", "    "
+			}
+
+			entry, direct := mf.Skills[source]
+			locked, installed := lock.Skills[source]
+			if !direct {
+				entry = locked
+			}
+
+			fmt.Printf("%s%s  %s\n", branch, entry.DirName(source), treeVersion(source, entry, locked, direct, installed))
+
+			var notes []string
+			if requires := lock.Requires[source]; len(requires) > 0 {
+				notes = append(notes, "requer: "+strings.Join(skillNames(lock, requires), ", "))
+			}
+			if !direct {
+				notes = append(notes, "dependência de: "+strings.Join(skillNames(lock, requiredBy(lock, source)), ", "))
+			}
+			if origin := entry.Origin; origin.Source != "" {
+				notes = append(notes, "ejetada de: "+origin.Source+" "+commitLabel(origin.Commit, origin.Version))
+			}
+			if entry.Patch != "" {
+				notes = append(notes, "patch: "+entry.Patch)
+			}
+			for k, note := range notes {
+				sub := "├── "
+				if k == len(notes)-1 {
+					sub = "└── "
+				}
+				fmt.Printf("%s%s%s\n", indent, sub, note)
+			}
+		}
+	}
+	printGlobalSkills(lock)
+	return nil
+}
+
+// printGlobalSkills lists the global skills after the ones of the project
+// (lock), marking those a project skill shadows.
+func printGlobalSkills(lock *manifest.Manifest) {
+	if globalMode {
+		return
+	}
+	root, _, global, err := globalSkills()
+	if err != nil || len(global.Skills) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🌐 Globais (%s)\n", root)
+	sources := global.SortedSources()
+	for i, source := range sources {
+		branch := "├── "
+		if i == len(sources)-1 {
+			branch = "└── "
+		}
+
+		entry := global.Skills[source]
+		name := entry.DirName(source)
+		version := "(local)"
+		if !strings.HasPrefix(source, "local@") {
+			version = commitLabel(entry.Ref, entry.Version)
+		}
+		if len(lock.FindAll(name)) > 0 {
+			version += " — sobreposta pela skill do projeto"
+		}
+		fmt.Printf("%s%s  %s\n", branch, name, version)
+	}
+}
+
+// treeVersion describes the version of a skill: what sklfile.json asks for
+// and what sklfile.lock holds.
+func treeVersion(source string, entry, locked manifest.Entry, direct, installed bool) string {
+	if strings.HasPrefix(source, "local@") {
+		return "(local)"
+	}
+	if !installed {
+		return entry.Ref + " → não instalada (execute skl update)"
+	}
+	label := commitLabel(locked.Ref, locked.Version)
+	if !direct {
+		return label + " (indireta)"
+	}
+	return entry.Ref + " → " + label
+}
+
+// allSources returns the sources of the manifest and the lock, sorted.
+func allSources(mf, lock *manifest.Manifest) []string {
+	seen := make(map[string]bool)
+	var sources []string
+	for _, m := range []*manifest.Manifest{mf, lock} {
+		for source := range m.Skills {
+			if !seen[source] {
+				seen[source] = true
+				sources = append(sources, source)
+			}
+		}
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// repoLabel names the repository a source comes from (e.g.
+// "github@empresa/repo-skills"), used to group skills.
+func repoLabel(source string) string {
+	ref, err := parser.Parse(source)
+	if err != nil {
+		return source
+	}
+	switch ref.Provider {
+	case parser.ProviderLocal:
+		return "local (skills do projeto)"
+	case parser.ProviderFile:
+		return "file: (sistema de arquivos)"
+	case parser.ProviderGit:
+		return "git+" + ref.Location
+	}
+	return ref.Provider + "@" + ref.User + "/" + ref.Repo
+}
+
+// skillNames returns the directory names of sources, as found in m.
+func skillNames(m *manifest.Manifest, sources []string) []string {
+	names := make([]string, len(sources))
+	for i, source := range sources {
+		names[i] = m.Skills[source].DirName(source)
+	}
+	return names
+}
@@ -1,17 +1,24 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 
-	"github.com/rduarte/skl/internal/catalog"
 	"github.com/rduarte/skl/internal/installer"
 	"github.com/rduarte/skl/internal/manifest"
 	"github.com/rduarte/skl/internal/parser"
 	"github.com/rduarte/skl/internal/provider"
+	"github.com/rduarte/skl/internal/semver"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var updateCmd = &cobra.Command{
@@ -22,18 +29,56 @@ e executa as ações necessárias:
 
   • Nova skill no sklfile.json → instala
   • Skill removida do sklfile.json → remove
-  • Versão alterada → remove e reinstala
-
-Ao final, atualiza o sklfile.lock para refletir o estado atual.`,
-	Args: cobra.NoArgs,
-	RunE: runUpdate,
+  • Versão alterada → reinstala (a versão anterior só é substituída
+    se a nova for baixada com sucesso)
+  • Faixa semver (ex: ^1.2) com tag nova compatível → atualiza para a
+    maior tag que satisfaz a faixa
+
+As verificações remotas e as instalações rodam em paralelo (veja --jobs).
+Ao final, atualiza o sklfile.lock para refletir o estado atual.
+
+Use --dry-run para ver o plano completo sem alterar nada e --changelog para
+listar os commits de cada skill atualizada. Quando o plano remove diretórios
+de skills, o comando pede confirmação (ou --yes).
+
+Skills alteradas desde a instalação não são sobrescritas: o comando pergunta
+se deve mantê-las, sobrescrevê-las ou salvar a cópia local antes (backup
+.orig ou patch em .agent/backups/). Sem terminal, elas são mantidas; use
+--force ou --on-modified=keep|overwrite|backup|patch para decidir.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runUpdate,
 }
 
+var (
+	updateJobs      int
+	updateDryRun    bool
+	updateYes       bool
+	updateChangelog bool
+	updateForce     bool
+	updateModified  string
+)
+
 func init() {
+	addGlobalFlag(updateCmd, true)
 	rootCmd.AddCommand(updateCmd)
+	updateCmd.Flags().IntVarP(&updateJobs, "jobs", "j", 4, "Número máximo de skills processadas em paralelo")
+	updateCmd.Flags().BoolVar(&updateDryRun, "dry-run", false, "Apenas exibe o plano de alterações, sem modificar o projeto")
+	updateCmd.Flags().BoolVarP(&updateYes, "yes", "y", false, "Confirma automaticamente a remoção de skills")
+	updateCmd.Flags().BoolVar(&updateChangelog, "changelog", false, "Lista os commits de cada skill a ser atualizada")
+	updateCmd.Flags().BoolVarP(&updateForce, "force", "f", false, "Sobrescreve skills modificadas localmente (o mesmo que --on-modified=overwrite)")
+	updateCmd.Flags().StringVar(&updateModified, "on-modified", "", "O que fazer com skills modificadas localmente: keep, overwrite, backup ou patch (padrão: perguntar)")
 }
 
 func runUpdate(cmd *cobra.Command, args []string) error {
+	if err := validateModifiedAction(updateModified); err != nil {
+		return err
+	}
+	onModified := updateModified
+	if updateForce {
+		onModified = modifiedOverwrite
+	}
+
 	// 0. Check if manifest exists
 	if _, err := os.Stat(manifest.FileName); os.IsNotExist(err) {
 		fmt.Printf("⚠  Arquivo %s não encontrado neste diretório.\n", manifest.FileName)
@@ -53,64 +98,142 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Resolve hashes for desired state to detect remote changes
+	// Resolve hashes for desired state to detect remote changes.
+	// Each "git ls-remote" runs in the worker pool; warnings are collected
+	// and printed in manifest order.
 	fmt.Println("🔍 Verificando atualizações remotas...")
-	resolvedDesired := &manifest.Manifest{Skills: make(map[string]string)}
-	for source, gitRef := range desired.Skills {
-		if strings.HasPrefix(source, "local@") {
-			resolvedDesired.Skills[source] = gitRef
-			continue
-		}
-
-		ref, err := parser.Parse(source)
-		if err != nil {
-			resolvedDesired.Skills[source] = gitRef
-			continue
+	sources := desired.SortedSources()
+	resolved := make([]manifest.Entry, len(sources))
+	warnings := make([]error, len(sources))
+	runJobs(updateJobs, len(sources), func(i int) {
+		resolved[i], warnings[i] = resolveDesired(sources[i], desired.Skills[sources[i]])
+	})
+
+	resolvedDesired := &manifest.Manifest{Targets: desired.Targets, Skills: make(map[string]manifest.Entry)}
+	for i, source := range sources {
+		resolvedDesired.Skills[source] = resolved[i]
+		if warnings[i] != nil {
+			fmt.Printf("⚠️  Não foi possível verificar atualização para %q: %v\n", source, warnings[i])
+			// Keep what is installed rather than reinstalling an unresolved ref
+			if old, ok := locked.Skills[source]; ok && !old.Indirect {
+				resolvedDesired.Skills[source] = old
+			}
 		}
+	}
 
-		prov, err := provider.New(ref.Provider)
-		if err != nil {
-			resolvedDesired.Skills[source] = gitRef
-			continue
+	// Add the skills they require (SKILL.md or skill.json) as indirect
+	// entries; cycles and version conflicts abort before anything changes.
+	// The dependencies already recorded are the fallback for skills whose
+	// SKILL.md cannot be read now.
+	resolvedDesired.Requires = make(map[string][]string, len(locked.Requires))
+	for source, reqs := range locked.Requires {
+		resolvedDesired.Requires[source] = reqs
+	}
+	if _, err := resolveRequires(resolvedDesired, locked, sources); err != nil {
+		return err
+	}
+	for source := range resolvedDesired.Requires {
+		if _, ok := resolvedDesired.Skills[source]; !ok {
+			delete(resolvedDesired.Requires, source) // no longer installed
 		}
+	}
 
-		cloneURL := prov.CloneURL(ref.User, ref.Repo)
-		hash, err := installer.ResolveRef(cloneURL, gitRef)
-		if err != nil {
-			fmt.Printf("⚠️  Não foi possível verificar atualização para %q: %v\n", source, err)
-			resolvedDesired.Skills[source] = gitRef
-		} else {
-			resolvedDesired.Skills[source] = hash
-		}
+	// Skills from different repositories may share a name: each one must
+	// end up in its own directory
+	if err := checkCollisions(resolvedDesired); err != nil {
+		return err
 	}
 
 	// Compute diff using resolved hashes
 	toInstall, toRemove, toUpgrade := diffManifests(resolvedDesired, locked)
 
+	// Check the skills that stay as they are against the digest recorded at
+	// install time: missing directories are reinstalled, edits are reported
+	toRepair, modified := verifyUnchanged(resolvedDesired, locked)
+	for _, source := range modified {
+		fmt.Printf("⚠️  %q foi modificada localmente desde a instalação (detalhes: skl verify)\n", manifest.SkillName(source))
+	}
+	if len(toRepair) > 0 {
+		toInstall = append(toInstall, toRepair...)
+		sort.Strings(toInstall)
+	}
+
+	// Copies left in target directories no longer used are deleted too
+	stale := staleCopies(resolvedDesired, locked)
+
 	total := len(toInstall) + len(toRemove) + len(toUpgrade)
-	if total == 0 {
+	retarget := !slices.Equal(desired.Targets, locked.Targets)
+	if total == 0 && !retarget && len(stale) == 0 {
+		// Copies missing from the other target directories are restored
+		// like missing skills, without asking
+		if !updateDryRun {
+			for _, e := range syncTargets(resolvedDesired, locked, nil) {
+				fmt.Println(e)
+			}
+		}
 		fmt.Println("✅ Tudo sincronizado — nenhuma alteração necessária")
+		if !updateDryRun {
+			refreshIndex(desired.Index)
+		}
 		return nil
 	}
 
+	// Upgrades replace the whole directory: find the ones that would
+	// discard edits made since installation
+	edited := findModified(toUpgrade, locked)
+
 	fmt.Printf("📋 Alterações detectadas:\n")
-	if len(toInstall) > 0 {
-		fmt.Printf("   + %d skill(s) para instalar\n", len(toInstall))
+	printPlan(toInstall, toRemove, toUpgrade, stale, edited, resolvedDesired, locked)
+	fmt.Println()
+
+	if updateChangelog {
+		for _, source := range toUpgrade {
+			if err := printChangelog(os.Stdout, source, locked.Skills[source], resolvedDesired.Skills[source]); err != nil {
+				fmt.Printf("⚠️  Não foi possível obter o histórico de %q: %v\n", source, err)
+			}
+			fmt.Println()
+		}
 	}
-	if len(toRemove) > 0 {
-		fmt.Printf("   - %d skill(s) para remover\n", len(toRemove))
+
+	if updateDryRun {
+		fmt.Println("🧪 --dry-run: nenhuma alteração foi feita")
+		return nil
 	}
-	if len(toUpgrade) > 0 {
-		fmt.Printf("   ↑ %d skill(s) para atualizar\n", len(toUpgrade))
+
+	// Deleting directories is the only destructive step: ask first
+	if deletions := countDeletions(toRemove) + len(stale); deletions > 0 && !updateYes {
+		ok, err := confirm(fmt.Sprintf("%d diretório(s) de skill serão removidos. Continuar?", deletions))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("✋ Atualização cancelada — nenhuma alteração foi feita")
+			return nil
+		}
+		fmt.Println()
 	}
-	fmt.Println()
 
-	var errors []string
+	// Modified skills are only replaced when asked to (--force,
+	// --on-modified or the interactive prompt); kept ones stay locked
+	// at their current version
+	toUpgrade, kept, err := protectModified(toUpgrade, edited, locked, onModified)
+	if err != nil {
+		return err
+	}
+	for _, source := range kept {
+		resolvedDesired.Skills[source] = locked.Skills[source]
+	}
+	total -= len(kept)
+	if len(kept) > 0 {
+		fmt.Println()
+	}
+
+	var failures []string
 	success := 0
 
 	// 1. Remove skills that were removed from sklfile.json
 	for _, source := range toRemove {
-		skill := manifest.SkillName(source)
+		skill := locked.Skills[source].DirName(source)
 
 		// If it's a local skill, we just stop tracking it in the lock file (happens later)
 		// but we do NOT delete the directory.
@@ -121,49 +244,109 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		}
 
 		fmt.Printf("🗑️  Removendo %q...\n", skill)
-		if err := removeSkillDir(skill); err != nil {
-			errors = append(errors, fmt.Sprintf("  ✗ %s: %v", skill, err))
+		if err := removeSkillDir(source, locked.Skills[source]); err != nil {
+			failures = append(failures, fmt.Sprintf("  ✗ %s: %v", skill, err))
 			continue
 		}
 		success++
 	}
 
-	// 2. Upgrade skills (remove old + install new)
-	for _, source := range toUpgrade {
-		skill := manifest.SkillName(source)
-		oldRef := locked.Skills[source]
-		newRef := desired.Skills[source]
-		fmt.Printf("↑  Atualizando %q (%s → %s)...\n", skill, oldRef, newRef)
+	// 2-3. Upgrade (atomically replace old) and install skills in the
+	// worker pool. Each skill writes to its own buffer, flushed as a block
+	// once it finishes, so logs from different skills never interleave.
+	tasks := append(append([]string{}, toUpgrade...), toInstall...)
+	results := make([]error, len(tasks))
+	commits := make([]string, len(tasks))
+	var outMu sync.Mutex
+	runJobs(updateJobs, len(tasks), func(i int) {
+		source := tasks[i]
+		entry, ok := desired.Skills[source]
+		if !ok {
+			// Indirect dependency: install the commit it resolved to
+			entry = resolvedDesired.Skills[source]
+		}
+		skill := entry.DirName(source)
 
-		// Remove old version
-		if err := removeSkillDir(skill); err != nil {
-			errors = append(errors, fmt.Sprintf("  ✗ %s: %v", skill, err))
-			continue
+		// Ranges install the tag they resolved to above
+		if version := resolvedDesired.Skills[source].Version; version != "" {
+			entry.Ref = version
 		}
 
-		// Install new version
-		if err := installSkill(source, newRef); err != nil {
-			errors = append(errors, fmt.Sprintf("  ✗ %s: %v", skill, err))
+		var buf bytes.Buffer
+		upgrade := i < len(toUpgrade)
+		if upgrade {
+			old := locked.Skills[source]
+			oldRef := old.Ref
+			if old.Version != "" {
+				oldRef = old.Version
+			}
+			fmt.Fprintf(&buf, "↑  Atualizando %q (%s → %s)...\n", skill, oldRef, entry.Ref)
+		} else {
+			fmt.Fprintf(&buf, "📦 Instalando %q...\n", skill)
+		}
+
+		// Install new version; the old one is swapped out only on success
+		commits[i], results[i] = installSkill(&buf, source, entry)
+		if results[i] == nil && upgrade {
+			// A new alias or target leaves the previous copy behind
+			results[i] = removeMovedSkill(source, locked.Skills[source], entry)
+		}
+		if results[i] != nil {
+			fmt.Fprintf(&buf, "❌ %s: %v\n", skill, results[i])
+		}
+		fmt.Fprintln(&buf)
+
+		outMu.Lock()
+		os.Stdout.Write(buf.Bytes())
+		outMu.Unlock()
+	})
+
+	installed := make(map[string]bool)
+	for i, err := range results {
+		source := tasks[i]
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("  ✗ %s: %v", manifest.SkillName(source), err))
+
+			// The previous version (if any) was kept on disk, so keep its
+			// lock entry too and let the next update retry
+			if old, ok := locked.Skills[source]; ok {
+				resolvedDesired.Skills[source] = old
+			} else {
+				delete(resolvedDesired.Skills, source)
+			}
 			continue
 		}
+
+		// Lock the commit that was checked out, even if the ref moved
+		// after the plan was made
+		if commits[i] != "" {
+			entry := resolvedDesired.Skills[source]
+			entry.Ref = commits[i]
+			resolvedDesired.Skills[source] = entry
+		}
+		installed[source] = true
 		success++
-		fmt.Println()
 	}
 
-	// 3. Install new skills
-	for _, source := range toInstall {
-		skill := manifest.SkillName(source)
-		gitRef := desired.Skills[source]
-		fmt.Printf("📦 Instalando %q...\n", skill)
-
-		if err := installSkill(source, gitRef); err != nil {
-			errors = append(errors, fmt.Sprintf("  ✗ %s: %v", skill, err))
+	// Record the digest of what was just installed; untouched skills keep
+	// the one recorded when they were installed
+	for source := range resolvedDesired.Skills {
+		if strings.HasPrefix(source, "local@") {
 			continue
 		}
-		success++
-		fmt.Println()
+		if installed[source] {
+			if err := recordIntegrity(resolvedDesired, source); err != nil {
+				failures = append(failures, fmt.Sprintf("  ✗ %s: %v", manifest.SkillName(source), err))
+			}
+		} else if sum, ok := locked.Integrity[source]; ok {
+			resolvedDesired.SetIntegrity(source, sum)
+		}
 	}
 
+	// Copy the skills to new target directories and drop the copies left in
+	// the ones no longer listed in sklfile.json
+	failures = append(failures, syncTargets(resolvedDesired, locked, installed)...)
+
 	// 4. Update sklfile.lock with the hashes we already resolved
 	if err := resolvedDesired.SaveLock(); err != nil {
 		return fmt.Errorf("erro ao salvar %s: %w", manifest.LockFileName, err)
@@ -173,9 +356,13 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	fmt.Printf("📊 Resultado: %d/%d operação(ões) concluída(s)\n", success, total)
 	fmt.Printf("🔒 %s atualizado\n", manifest.LockFileName)
 
-	if len(errors) > 0 {
+	if len(kept) > 0 {
+		fmt.Printf("⏭️  %d skill(s) modificada(s) localmente não foram atualizadas (use --force ou --on-modified)\n", len(kept))
+	}
+
+	if len(failures) > 0 {
 		fmt.Println("\n⚠  Erros:")
-		for _, e := range errors {
+		for _, e := range failures {
 			fmt.Println(e)
 		}
 	}
@@ -183,9 +370,124 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	// Ensure lock file is ignored in .gitignore
 	_ = manifest.EnsureIgnoreLock()
 
+	refreshIndex(desired.Index)
 	return nil
 }
 
+// printPlan lists every planned action: the commits involved and the
+// directories that would be created or deleted.
+func printPlan(toInstall, toRemove, toUpgrade, stale []string, edited map[string]bool, desired, locked *manifest.Manifest) {
+	if !slices.Equal(desired.Targets, locked.Targets) {
+		fmt.Printf("   ↔ diretórios de destino: %s → %s\n",
+			strings.Join(locked.SkillsDirs(manifest.Entry{}), ", "), strings.Join(desired.SkillsDirs(manifest.Entry{}), ", "))
+	}
+	if len(stale) > 0 {
+		fmt.Printf("   - %d cópia(s) em diretórios de destino não mais usados\n", len(stale))
+		for _, path := range stale {
+			fmt.Printf("       apaga %s\n", path)
+		}
+	}
+	if len(toInstall) > 0 {
+		fmt.Printf("   + %d skill(s) para instalar\n", len(toInstall))
+		for _, source := range toInstall {
+			entry := desired.Skills[source]
+			note := ""
+			if _, ok := locked.Skills[source]; ok {
+				note = " (diretório ausente, reinstalar)"
+			} else if entry.Indirect {
+				note = " (dependência de " + dependentNames(desired, source) + ")"
+			}
+			fmt.Printf("       %s @ %s → %s%s\n", source, commitLabel(entry.Ref, entry.Version), planDir(source, entry), note)
+		}
+	}
+	if len(toRemove) > 0 {
+		fmt.Printf("   - %d skill(s) para remover\n", len(toRemove))
+		for _, source := range toRemove {
+			entry := locked.Skills[source]
+			if strings.HasPrefix(source, "local@") {
+				fmt.Printf("       %s: deixa de ser rastreada (%s é mantido)\n", source, planDir(source, entry))
+				continue
+			}
+			note := ""
+			if entry.Indirect {
+				note = " (dependência não mais necessária)"
+			}
+			fmt.Printf("       %s: apaga %s%s\n", source, planDir(source, entry), note)
+		}
+	}
+	if len(toUpgrade) > 0 {
+		fmt.Printf("   ↑ %d skill(s) para atualizar\n", len(toUpgrade))
+		for _, source := range toUpgrade {
+			prev, next := locked.Skills[source], desired.Skills[source]
+			note := ""
+			if edited[source] {
+				note = " ⚠️  modificada localmente"
+			}
+			fmt.Printf("       %s: %s → %s%s\n", source, commitLabel(prev.Ref, prev.Version), commitLabel(next.Ref, next.Version), note)
+			if prevDir, nextDir := planDir(source, prev), planDir(source, next); prevDir != nextDir {
+				fmt.Printf("         move %s → %s\n", prevDir, nextDir)
+			}
+		}
+	}
+}
+
+// planDir returns the directories of an entry relative to the project.
+func planDir(source string, entry manifest.Entry) string {
+	return strings.Join(skillPaths(source, entry), ", ")
+}
+
+// countDeletions returns how many removals delete a directory (local@
+// skills are only untracked).
+func countDeletions(toRemove []string) int {
+	n := 0
+	for _, source := range toRemove {
+		if !strings.HasPrefix(source, "local@") {
+			n++
+		}
+	}
+	return n
+}
+
+// confirm asks a yes/no question on the terminal. Without a terminal to
+// ask on, it fails and points to --yes.
+func confirm(question string) (bool, error) {
+	answer, err := ask(question + " [s/N]")
+	if err == errNoTerminal {
+		return false, fmt.Errorf("o plano remove skills e não há um terminal para confirmar; use --yes para prosseguir")
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch answer {
+	case "s", "sim", "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// errNoTerminal is returned by ask when stdin is not a terminal.
+var errNoTerminal = errors.New("stdin não é um terminal")
+
+// ask prints a question and returns the answer typed on the terminal,
+// trimmed and lowercased (empty on EOF).
+func ask(question string) (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", errNoTerminal
+	}
+
+	fmt.Printf("❓ %s ", question)
+	answer, err := stdinReader.ReadString('\n')
+	if err != nil && answer == "" {
+		return "", nil
+	}
+	return strings.ToLower(strings.TrimSpace(answer)), nil
+}
+
+// stdinReader is shared by every prompt, so buffered input is not lost
+// between questions.
+var stdinReader = bufio.NewReader(os.Stdin)
+
 // diffManifests compares desired (sklfile.json) vs locked (sklfile.lock)
 // and returns lists of sources to install, remove, and upgrade.
 func diffManifests(desired, locked *manifest.Manifest) (toInstall, toRemove, toUpgrade []string) {
@@ -203,66 +505,204 @@ func diffManifests(desired, locked *manifest.Manifest) (toInstall, toRemove, toU
 		}
 	}
 
-	// In both, but different ref → upgrade
-	for source, desiredRef := range desired.Skills {
-		if lockedRef, exists := locked.Skills[source]; exists {
-			if desiredRef != lockedRef {
+	// In both, but different ref or install settings → upgrade
+	for source, desiredEntry := range desired.Skills {
+		if lockedEntry, exists := locked.Skills[source]; exists {
+			if desiredEntry != lockedEntry {
 				toUpgrade = append(toUpgrade, source)
 			}
 		}
 	}
 
+	// Sorted so plans, logs and summaries are deterministic
+	sort.Strings(toInstall)
+	sort.Strings(toRemove)
+	sort.Strings(toUpgrade)
 	return
 }
 
-// installSkill resolves provider and installs a skill.
-func installSkill(source, gitRef string) error {
-	fullRef := source
-	if gitRef != "" && gitRef != "*" {
-		fullRef += ":" + gitRef
+// verifyUnchanged checks the skills whose resolved ref matches the lock
+// against their recorded digest. It returns the sources whose directory is
+// missing (to be reinstalled) and those modified since installation.
+func verifyUnchanged(desired, locked *manifest.Manifest) (missing, modified []string) {
+	for _, source := range desired.SortedSources() {
+		if desired.Skills[source] != locked.Skills[source] {
+			continue
+		}
+		sum := locked.Integrity[source]
+		if sum == nil {
+			continue
+		}
+
+		state, _, err := checkSkill(source, locked.Skills[source], sum)
+		if err != nil {
+			continue
+		}
+		switch state {
+		case skillMissing:
+			missing = append(missing, source)
+		case skillModified:
+			modified = append(modified, source)
+		}
 	}
+	return
+}
+
+// resolveDesired returns the lock entry for a manifest entry: the commit
+// hash its ref currently points to and, for semver ranges, the highest
+// matching tag. When the remote can't be queried, the symbolic ref is kept
+// and the error is returned alongside it.
+func resolveDesired(source string, entry manifest.Entry) (manifest.Entry, error) {
+	unresolved := entry.Locked(entry.Ref, "")
 
-	ref, err := parser.Parse(fullRef)
+	ref, err := parser.Parse(source)
 	if err != nil {
-		return err
+		return unresolved, err
 	}
 
-	// Local skills are already on disk, nothing to install
-	if ref.Provider == "local" {
-		return nil
+	// Local and filesystem skills have no remote to resolve
+	if ref.Provider == parser.ProviderLocal || ref.Provider == parser.ProviderFile {
+		return unresolved, nil
 	}
 
-	prov, err := provider.New(ref.Provider)
+	cloneURL, _, err := remoteURLs(ref)
 	if err != nil {
-		return err
+		return unresolved, err
 	}
 
-	cloneURL := prov.CloneURL(ref.User, ref.Repo)
-	repoURL := prov.RepoURL(ref.User, ref.Repo)
-
-	// Resolve skill path (via catalog.json if available)
-	var overridePath string
-	cat, err := catalog.Fetch(prov, ref.User, ref.Repo, ref.Tag)
-	if err == nil && cat != nil {
-		if entry := cat.Find(ref.Skill); entry != nil && entry.Path != "" {
-			overridePath = entry.Path
+	if semver.IsRange(entry.Ref) {
+		tag, hash, err := installer.ResolveVersion(cloneURL, entry.Ref)
+		if err != nil {
+			return unresolved, err
 		}
+		return entry.Locked(hash, tag), nil
+	}
+
+	hash, err := installer.ResolveRef(cloneURL, entry.Ref)
+	if err != nil {
+		return unresolved, err
+	}
+	return entry.Locked(hash, ""), nil
+}
+
+// runJobs calls fn for every index in [0, n) using at most jobs goroutines
+// and waits for all of them to finish.
+func runJobs(jobs, n int, fn func(i int)) {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
 	}
 
-	fmt.Printf("🔗 Clone URL: %s\n", cloneURL)
-	return installer.Install(cloneURL, repoURL, ref.Skill, ref.Tag, overridePath, true)
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
 }
 
-// removeSkillDir removes the skill directory from .agent/skills/.
-func removeSkillDir(skill string) error {
-	cwd, err := os.Getwd()
+// installSkill resolves provider and installs a skill, writing progress to w.
+// It returns the commit the skill was checked out from, empty for local and
+// filesystem skills.
+func installSkill(w io.Writer, source string, entry manifest.Entry) (string, error) {
+	ref, err := parser.Parse(source)
 	if err != nil {
-		return err
+		return "", err
+	}
+	if entry.Ref != "" && entry.Ref != "*" {
+		ref.Tag = entry.Ref
+	}
+
+	switch ref.Provider {
+	case parser.ProviderLocal:
+		// Local skills are already on disk, only their copies are made
+		return "", mirrorSkill(source, entry, false)
+	case parser.ProviderFile:
+		_, err := installer.Install(installer.Options{
+			Skill:     entry.DirName(source),
+			SkillsDir: skillsDirs(entry)[0],
+			LocalPath: ref.Location,
+			Patch:     entry.Patch,
+			Force:     true,
+			Out:       w,
+		})
+		if err != nil {
+			return "", err
+		}
+		return "", mirrorSkill(source, entry, false)
+	}
+
+	cloneURL, repoURL, err := remoteURLs(ref)
+	if err != nil {
+		return "", err
+	}
+	if ref.Tag, err = resolveTag(cloneURL, ref.Tag); err != nil {
+		return "", err
+	}
+
+	// Resolve skill path (manifest entry, git+ fragment or catalog.json)
+	overridePath := entry.Path
+	if overridePath == "" {
+		overridePath = skillRepoPath(ref)
+	}
+
+	fmt.Fprintf(w, "🔗 Clone URL: %s\n", provider.Redact(cloneURL))
+	commit, err := installer.Install(installer.Options{
+		Skill:        entry.DirName(source),
+		SkillsDir:    skillsDirs(entry)[0],
+		CloneURL:     cloneURL,
+		RepoURL:      repoURL,
+		RepoSkill:    ref.Skill,
+		Tag:          ref.Tag,
+		OverridePath: overridePath,
+		Patch:        entry.Patch,
+		Force:        true,
+		Out:          w,
+	})
+	if err != nil {
+		return "", err
+	}
+	return commit, mirrorSkill(source, entry, false)
+}
+
+// removeSkillDir removes the directories a skill was installed in.
+func removeSkillDir(source string, entry manifest.Entry) error {
+	for _, dir := range skillPaths(source, entry) {
+		if _, err := os.Stat(dir); err == nil {
+			if err := os.RemoveAll(dir); err != nil {
+				return err
+			}
+		}
 	}
+	return nil
+}
 
-	dir := filepath.Join(cwd, ".agent", "skills", skill)
-	if _, err := os.Stat(dir); err == nil {
-		return os.RemoveAll(dir)
+// removeMovedSkill removes the previous copies of a skill whose alias or
+// target directory changed between prev and next.
+func removeMovedSkill(source string, prev, next manifest.Entry) error {
+	keep := make(map[string]bool)
+	for _, dir := range skillPaths(source, next) {
+		keep[dir] = true
+	}
+	for _, dir := range skillPaths(source, prev) {
+		if keep[dir] {
+			continue
+		}
+		if _, err := os.Stat(dir); err == nil {
+			if err := os.RemoveAll(dir); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
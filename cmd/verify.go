@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rduarte/skl/internal/integrity"
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verifica se as skills instaladas foram alteradas",
+	Long: `Compara o conteúdo de cada skill instalada com o hash de integridade
+(SHA-256) gravado no sklfile.lock no momento da instalação.
+
+Arquivos modificados, ausentes ou extras são listados e o comando termina com
+código de saída diferente de zero, permitindo usá-lo como verificação no CI.
+Uma skill sem registro de integridade no lock também conta como falha: não há
+como garantir que ela não foi alterada.
+
+O sklfile.lock é adicionado ao .gitignore pelo skl: para verificar as skills
+no CI, versione-o. Sem ele, o comando falha se o sklfile.json tiver skills.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runVerify,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	// Without the lock there is nothing to compare with: in CI this means it
+	// was not committed, not that the skills are intact
+	if _, err := os.Stat(manifest.LockFileName); os.IsNotExist(err) {
+		mf, err := manifest.Load()
+		if err != nil {
+			return err
+		}
+		if len(mf.Skills) > 0 {
+			return fmt.Errorf("%s não encontrado: não há com o que comparar as skills do %s (para verificar no CI, versione o %s e remova-o do .gitignore)",
+				manifest.LockFileName, manifest.FileName, manifest.LockFileName)
+		}
+	}
+
+	lock, err := manifest.LoadLock()
+	if err != nil {
+		return err
+	}
+
+	if len(lock.Skills) == 0 {
+		fmt.Printf("ℹ️  Nenhuma skill registrada no %s.\n", manifest.LockFileName)
+		return nil
+	}
+
+	problems := 0
+	for _, source := range lock.SortedSources() {
+		// Local skills are owned by the project, there is nothing to compare to
+		if strings.HasPrefix(source, "local@") {
+			continue
+		}
+
+		entry := lock.Skills[source]
+		skill := entry.DirName(source)
+		sum := lock.Integrity[source]
+		if sum == nil {
+			// Nothing to compare with is not the same as intact
+			fmt.Printf("❌ %s: sem registro de integridade (reinstale para registrar)\n", skill)
+			problems++
+			continue
+		}
+
+		state, report, err := checkSkill(source, entry, sum)
+		if err != nil {
+			return err
+		}
+
+		switch state {
+		case skillMissing:
+			fmt.Printf("❌ %s: diretório ausente\n", skill)
+			problems++
+		case skillModified:
+			fmt.Printf("❌ %s: modificada localmente\n", skill)
+			printReport(report)
+			problems++
+		default:
+			fmt.Printf("✅ %s\n", skill)
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("%d skill(s) não conferem com o %s", problems, manifest.LockFileName)
+	}
+	return nil
+}
+
+// skillState describes an installed skill compared with its lock entry.
+type skillState int
+
+const (
+	skillIntact skillState = iota
+	skillModified
+	skillMissing
+)
+
+// checkSkill compares the directory of an installed skill with the digest
+// recorded at install time.
+func checkSkill(source string, entry manifest.Entry, sum *integrity.Sum) (skillState, *integrity.Report, error) {
+	dir, err := installedPath(source, entry)
+	if err != nil {
+		return skillIntact, nil, err
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return skillMissing, nil, nil
+	}
+
+	report, err := integrity.Verify(dir, sum)
+	if err != nil {
+		return skillIntact, nil, err
+	}
+	if !report.Clean() {
+		return skillModified, report, nil
+	}
+	return skillIntact, report, nil
+}
+
+// recordIntegrity hashes an installed skill and stores the digest in lock.
+// The skill is looked up through its lock entry, which must already be set.
+func recordIntegrity(lock *manifest.Manifest, source string) error {
+	dir, err := installedPath(source, lock.Skills[source])
+	if err != nil {
+		return err
+	}
+
+	sum, err := integrity.Hash(dir)
+	if err != nil {
+		return err
+	}
+	lock.SetIntegrity(source, sum)
+	return nil
+}
+
+// printReport lists the files that differ from the recorded digest.
+func printReport(r *integrity.Report) {
+	for _, f := range r.Modified {
+		fmt.Printf("     ~ %s (modificado)\n", f)
+	}
+	for _, f := range r.Missing {
+		fmt.Printf("     - %s (ausente)\n", f)
+	}
+	for _, f := range r.Extra {
+		fmt.Printf("     + %s (extra)\n", f)
+	}
+}
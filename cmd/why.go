@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var whyCmd = &cobra.Command{
+	Use:   "why <skill-name | diretório>",
+	Short: "Explica por que uma skill está instalada no projeto",
+	Long: `Mostra qual entrada do sklfile.json é responsável por um diretório de skill:
+o repositório e a versão de origem, o commit registrado no sklfile.lock e, para
+dependências, a cadeia de skills que a requer.
+
+Lê apenas o sklfile.json e o sklfile.lock: funciona sem acesso à rede.
+
+Exemplos:
+  skl why style-guide
+  skl why .agent/skills/style-guide`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		lock, err := manifest.LoadLock()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		var suggestions []string
+		for source, entry := range lock.Skills {
+			if name := entry.DirName(source); strings.HasPrefix(name, toComplete) {
+				suggestions = append(suggestions, name)
+			}
+		}
+		return suggestions, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: runWhy,
+}
+
+func init() {
+	rootCmd.AddCommand(whyCmd)
+}
+
+func runWhy(cmd *cobra.Command, args []string) error {
+	// Accept a path to the skill directory as well as its name or source
+	skill := args[0]
+	if !strings.Contains(skill, "@") && !strings.HasPrefix(skill, "file:") {
+		skill = filepath.Base(filepath.Clean(skill))
+	}
+
+	mf, err := manifest.Load()
+	if err != nil {
+		return err
+	}
+	lock, err := manifest.LoadLock()
+	if err != nil {
+		return err
+	}
+
+	source, err := findSkill(mf, skill)
+	if err != nil {
+		return err
+	}
+	if source == "" {
+		if source, err = findSkill(lock, skill); err != nil {
+			return err
+		}
+	}
+	if source == "" {
+		for _, dir := range skillsDirs(manifest.Entry{}) {
+			dir = filepath.Join(dir, skill)
+			if _, err := os.Stat(dir); err == nil {
+				return fmt.Errorf("%s não é gerenciado pelo skl (nem no %s, nem no %s)\n\n  Para indexá-lo como skill local, execute: skl setup",
+					filepath.ToSlash(dir), manifest.FileName, manifest.LockFileName)
+			}
+		}
+		return fmt.Errorf("skill %q não encontrada no %s nem no %s", skill, manifest.FileName, manifest.LockFileName)
+	}
+
+	entry, direct := mf.Skills[source]
+	locked, installed := lock.Skills[source]
+	if !direct {
+		entry = locked
+	}
+
+	fmt.Printf("📁 %s\n", planDir(source, entry))
+	fmt.Printf("   Origem:      %s\n", source)
+	fmt.Printf("   Repositório: %s\n", repoLabel(source))
+	if strings.HasPrefix(source, "local@") {
+		fmt.Printf("   Versão:      skill local, mantida no próprio projeto\n")
+	} else {
+		if direct {
+			fmt.Printf("   Versão:      %s (%s)\n", entry.Ref, manifest.FileName)
+		}
+		if installed {
+			fmt.Printf("   Instalada:   %s (%s)\n", commitLabel(locked.Ref, locked.Version), manifest.LockFileName)
+		} else {
+			fmt.Printf("   Instalada:   não (execute skl update)\n")
+		}
+	}
+	if origin := entry.Origin; origin.Source != "" {
+		fmt.Printf("   Ejetada de:  %s %s\n", origin.Source, commitLabel(origin.Commit, origin.Version))
+	}
+	if entry.Patch != "" {
+		fmt.Printf("   Patch:       %s\n", entry.Patch)
+	}
+	fmt.Println()
+
+	chains := dependencyChains(mf, lock, source)
+	switch {
+	case direct && len(chains) == 0:
+		fmt.Printf("✅ Listada diretamente no %s\n", manifest.FileName)
+	case direct:
+		fmt.Printf("✅ Listada diretamente no %s e também requerida por:\n", manifest.FileName)
+	case len(chains) == 0:
+		fmt.Printf("⚠️  Dependência que nenhuma skill requer mais — o próximo skl update a remove\n")
+	default:
+		fmt.Printf("🔗 Dependência indireta, requerida por:\n")
+	}
+	for _, chain := range chains {
+		fmt.Printf("   %s\n", strings.Join(skillNames(lock, chain), " → "))
+	}
+	return nil
+}
+
+// dependencyChains returns every path through lock.Requires from an entry of
+// the manifest down to source (e.g. [app guide lint]).
+func dependencyChains(mf, lock *manifest.Manifest, source string) [][]string {
+	var chains [][]string
+	onPath := map[string]bool{source: true}
+
+	var walk func(path []string)
+	walk = func(path []string) {
+		for _, from := range requiredBy(lock, path[0]) {
+			if onPath[from] {
+				continue // cycle
+			}
+			next := append([]string{from}, path...)
+			if _, direct := mf.Skills[from]; direct {
+				chains = append(chains, next)
+			}
+			onPath[from] = true
+			walk(next)
+			onPath[from] = false
+		}
+	}
+	walk([]string{source})
+	return chains
+}
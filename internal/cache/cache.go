@@ -0,0 +1,452 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// headRef stores the commit the remote HEAD pointed to on the last fetch.
+const headRef = "refs/skl/HEAD"
+
+// fetchRefspecs mirror the remote branches, tags and HEAD into the cache.
+var fetchRefspecs = []string{
+	"+HEAD:" + headRef,
+	"+refs/heads/*:refs/heads/*",
+	"+refs/tags/*:refs/tags/*",
+}
+
+var (
+	mu      sync.Mutex
+	locks   = map[string]*sync.Mutex{}
+	fetched = map[string]bool{}
+)
+
+// Repo is a bare, blob-less mirror of a remote repository kept under the
+// cache directory. Blobs are fetched lazily, only for the paths checked out.
+type Repo struct {
+	Dir string // path to the bare repository
+	URL string // clone URL without credentials
+
+	header string // HTTP auth header derived from credentials in the clone URL
+}
+
+// GitError carries the stderr of a failed git command, so callers can turn
+// it into a user-friendly message.
+type GitError struct {
+	Stderr string
+	Err    error
+}
+
+func (e *GitError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Err, strings.TrimSpace(e.Stderr))
+}
+
+func (e *GitError) Unwrap() error { return e.Err }
+
+// Entry describes a cached repository.
+type Entry struct {
+	Dir      string
+	URL      string
+	Size     int64
+	LastUsed time.Time
+}
+
+// Dir returns the cache root (e.g. ~/.cache/skl on Linux).
+func Dir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("erro ao localizar diretório de cache: %w", err)
+	}
+	return filepath.Join(dir, "skl"), nil
+}
+
+func reposDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "repos"), nil
+}
+
+// Key returns the cache key for a clone URL. Credentials are ignored, so the
+// same repository maps to the same entry regardless of the token in use.
+func Key(cloneURL string) string {
+	clean, _ := splitCredentials(cloneURL)
+	sum := sha256.Sum256([]byte(clean))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Open returns the cached mirror of cloneURL, cloning it on first use and
+// fetching it incrementally afterwards. Each repository is fetched at most
+// once per process, so installing many skills from the same repo only hits
+// the network once.
+func Open(cloneURL string) (*Repo, error) {
+	root, err := reposDir()
+	if err != nil {
+		return nil, err
+	}
+
+	clean, header := splitCredentials(cloneURL)
+	r := &Repo{
+		Dir:    filepath.Join(root, Key(cloneURL)+".git"),
+		URL:    clean,
+		header: header,
+	}
+
+	l := lockFor(r.Dir)
+	l.Lock()
+	defer l.Unlock()
+
+	mu.Lock()
+	done := fetched[r.Dir]
+	mu.Unlock()
+	if done {
+		return r, nil
+	}
+
+	if _, err := os.Stat(r.Dir); os.IsNotExist(err) {
+		err = r.clone(root)
+	} else {
+		err = r.fetch()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(r.Dir, now, now) // last use, for prune
+
+	mu.Lock()
+	fetched[r.Dir] = true
+	mu.Unlock()
+
+	return r, nil
+}
+
+// clone creates the mirror in a temp dir and renames it into place, so an
+// interrupted clone never leaves a broken cache entry behind.
+func (r *Repo) clone(root string) error {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de cache: %w", err)
+	}
+
+	tmp, err := os.MkdirTemp(root, ".clone-*")
+	if err != nil {
+		return fmt.Errorf("erro ao criar diretório temporário: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	args := append(r.authArgs(), "clone", "--bare", "--filter=blob:none", "--", r.URL, tmp)
+	if _, err := run(args...); err != nil {
+		return err
+	}
+	if _, err := run("--git-dir", tmp, "update-ref", headRef, "HEAD"); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, r.Dir); err != nil {
+		// Another skl process may have populated the entry meanwhile
+		if _, statErr := os.Stat(r.Dir); statErr == nil {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Repo) fetch() error {
+	args := append([]string{"fetch", "--prune", "--filter=blob:none", "--", "origin"}, fetchRefspecs...)
+	_, err := r.git(args...)
+	return err
+}
+
+// Resolve returns the commit hash for rev (branch, tag or commit).
+// An empty rev or "*" resolves to the remote HEAD.
+func (r *Repo) Resolve(rev string) (string, error) {
+	if rev == "" || rev == "*" {
+		rev = headRef
+	}
+	out, err := r.git("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("referência %q não encontrada no repositório", rev)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// HasPath reports whether path exists in the tree of commit.
+func (r *Repo) HasPath(commit, path string) bool {
+	out, err := r.git("ls-tree", commit, path)
+	return err == nil && strings.TrimSpace(out) != ""
+}
+
+// ListDirs returns the names of the directories directly under dir in commit.
+func (r *Repo) ListDirs(commit, dir string) ([]string, error) {
+	out, err := r.git("ls-tree", "-d", "--name-only", commit, strings.TrimSuffix(dir, "/")+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line != "" {
+			names = append(names, filepath.Base(line))
+		}
+	}
+	return names, nil
+}
+
+// ReadFile returns the contents of path as of commit.
+func (r *Repo) ReadFile(commit, path string) ([]byte, error) {
+	out, err := r.git("cat-file", "blob", commit+":"+path)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out), nil
+}
+
+// Checkout writes path as of commit into workTree (as workTree/path),
+// fetching only the blobs it needs.
+func (r *Repo) Checkout(commit, path, workTree string) error {
+	// A throwaway index keeps the shared cache free of per-checkout state
+	indexDir, err := os.MkdirTemp("", "skl-index-*")
+	if err != nil {
+		return fmt.Errorf("erro ao criar diretório temporário: %w", err)
+	}
+	defer os.RemoveAll(indexDir)
+
+	_, err = r.gitEnv("GIT_INDEX_FILE="+filepath.Join(indexDir, "index"), "--work-tree", workTree, "checkout", commit, "--", path)
+	return err
+}
+
+// DiffDir returns a unified diff from path as of commit to the files in dir
+// (typically an installed copy of path), with paths relative to both.
+// Extra args are passed to git diff (e.g. --stat).
+func (r *Repo) DiffDir(commit, path, dir string, args ...string) (string, error) {
+	// Snapshot dir into a tree object through a throwaway index
+	indexDir, err := os.MkdirTemp("", "skl-index-*")
+	if err != nil {
+		return "", fmt.Errorf("erro ao criar diretório temporário: %w", err)
+	}
+	defer os.RemoveAll(indexDir)
+
+	env := "GIT_INDEX_FILE=" + filepath.Join(indexDir, "index")
+	if _, err := r.gitEnv(env, "--work-tree", dir, "add", "-A", "-f", "--", "."); err != nil {
+		return "", err
+	}
+	tree, err := r.gitEnv(env, "--work-tree", dir, "write-tree")
+	if err != nil {
+		return "", err
+	}
+
+	diff := append(append([]string{"diff"}, args...), commit+":"+path, strings.TrimSpace(tree))
+	return r.git(diff...)
+}
+
+// Commit is a commit from the history of a cached repository.
+type Commit struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Date    string `json:"date"` // YYYY-MM-DD
+	Subject string `json:"subject"`
+}
+
+// Log returns the commits in from..to that touch path, newest first.
+func (r *Repo) Log(from, to, path string) ([]Commit, error) {
+	out, err := r.git("log", "--format=%H%x1f%an%x1f%ad%x1f%s", "--date=short", from+".."+to, "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		f := strings.Split(line, "\x1f")
+		if len(f) != 4 {
+			continue
+		}
+		commits = append(commits, Commit{Hash: f[0], Author: f[1], Date: f[2], Subject: f[3]})
+	}
+	return commits, nil
+}
+
+// DiffStat returns the file-level summary (git diff --stat) of path between
+// two commits. Only the blobs of changed files are fetched.
+func (r *Repo) DiffStat(from, to, path string) (string, error) {
+	out, err := r.git("diff", "--stat", "--relative="+path, from, to, "--", path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+// IsAncestor reports whether commit a is an ancestor of commit b.
+func (r *Repo) IsAncestor(a, b string) bool {
+	_, err := r.git("merge-base", "--is-ancestor", a, b)
+	return err == nil
+}
+
+// git runs a git command against the cached repository.
+func (r *Repo) git(args ...string) (string, error) {
+	full := append(r.authArgs(), append([]string{"--git-dir", r.Dir}, args...)...)
+	return run(full...)
+}
+
+// gitEnv runs a git command against the cached repository with an extra
+// environment variable. Git options (e.g. --work-tree) may lead args.
+func (r *Repo) gitEnv(env string, args ...string) (string, error) {
+	cmd := command(append(r.authArgs(), append([]string{"--git-dir", r.Dir}, args...)...)...)
+	cmd.Env = append(cmd.Env, env)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", &GitError{Stderr: stderr.String(), Err: err}
+	}
+	return stdout.String(), nil
+}
+
+// authArgs passes credentials as an HTTP header instead of storing them in
+// the repository config, so tokens never reach the disk.
+func (r *Repo) authArgs() []string {
+	if r.header == "" {
+		return nil
+	}
+	return []string{"-c", "http.extraHeader=" + r.header}
+}
+
+// List returns every cached repository, most recently used first.
+func List() ([]Entry, error) {
+	root, err := reposDir()
+	if err != nil {
+		return nil, err
+	}
+
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, d := range dirs {
+		if !d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(root, d.Name())
+		info, err := d.Info()
+		if err != nil {
+			continue
+		}
+		origin, _ := run("--git-dir", dir, "config", "remote.origin.url")
+		entries = append(entries, Entry{
+			Dir:      dir,
+			URL:      strings.TrimSpace(origin),
+			Size:     dirSize(dir),
+			LastUsed: info.ModTime(),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+	return entries, nil
+}
+
+// Clean removes the whole cache.
+func Clean() error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// Prune removes cached repositories not used for longer than maxAge and
+// returns the entries removed.
+func Prune(maxAge time.Duration) ([]Entry, error) {
+	entries, err := List()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []Entry
+	cutoff := time.Now().Add(-maxAge)
+	for _, e := range entries {
+		if e.LastUsed.Before(cutoff) {
+			if err := os.RemoveAll(e.Dir); err != nil {
+				return removed, err
+			}
+			removed = append(removed, e)
+		}
+	}
+	return removed, nil
+}
+
+// splitCredentials strips user:password from an HTTP(S) clone URL and turns
+// it into a Basic auth header.
+func splitCredentials(cloneURL string) (clean, header string) {
+	u, err := url.Parse(cloneURL)
+	if err != nil || u.User == nil {
+		return cloneURL, ""
+	}
+	pass, ok := u.User.Password()
+	if !ok {
+		return cloneURL, ""
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte(u.User.Username() + ":" + pass))
+	u.User = nil
+	return u.String(), "Authorization: Basic " + auth
+}
+
+func lockFor(dir string) *sync.Mutex {
+	mu.Lock()
+	defer mu.Unlock()
+	l, ok := locks[dir]
+	if !ok {
+		l = &sync.Mutex{}
+		locks[dir] = l
+	}
+	return l
+}
+
+// command builds a git command that never prompts for credentials.
+func command(args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	return cmd
+}
+
+func run(args ...string) (string, error) {
+	cmd := command(args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", &GitError{Stderr: stderr.String(), Err: err}
+	}
+	return stdout.String(), nil
+}
+
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
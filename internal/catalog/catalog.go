@@ -25,6 +25,8 @@ type SkillEntry struct {
 }
 
 // Fetch fetch the catalog.json from the given repository using the provider's RawURL.
+// The provider's token (see provider.Token), if any, is sent as a bearer token
+// so catalogs of private repositories can be read.
 func Fetch(prov provider.Provider, user, repo, ref string) (*Catalog, error) {
 	rawURL := prov.RawURL(user, repo, ref, "catalog.json")
 
@@ -32,7 +34,15 @@ func Fetch(prov provider.Provider, user, repo, ref string) (*Catalog, error) {
 		Timeout: 2 * time.Second, // Short timeout for autocomplete
 	}
 
-	resp, err := client.Get(rawURL)
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar catálogo: %w", err)
+	}
+	if token := provider.Token(prov); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar catálogo: %w", err)
 	}
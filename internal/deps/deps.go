@@ -0,0 +1,268 @@
+package deps
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rduarte/skl/internal/parser"
+)
+
+// Files a skill may declare its dependencies in.
+const (
+	SkillMD   = "SKILL.md"   // YAML front matter, "requires:" key
+	SkillJSON = "skill.json" // {"requires": [...]}
+)
+
+// Parse returns the requirements declared by a skill, given the contents of
+// its SKILL.md and skill.json (nil when the file does not exist). Entries of
+// both files are merged, in order and without duplicates.
+//
+// Each requirement is a skill reference as accepted by skl install
+// (e.g. "github@empresa/repo/style-guide:^1.0") or a bare skill name,
+// optionally with a tag, for a skill in the same repository
+// (e.g. "style-guide" or "style-guide:^1.0").
+func Parse(skillMD, skillJSON []byte) ([]string, error) {
+	var requires []string
+
+	if skillMD != nil {
+		reqs, err := frontMatter(skillMD)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao interpretar %s: %w", SkillMD, err)
+		}
+		requires = append(requires, reqs...)
+	}
+
+	if skillJSON != nil {
+		var meta struct {
+			Requires []string `json:"requires"`
+		}
+		if err := json.Unmarshal(skillJSON, &meta); err != nil {
+			return nil, fmt.Errorf("erro ao interpretar %s: %w", SkillJSON, err)
+		}
+		requires = append(requires, meta.Requires...)
+	}
+
+	seen := make(map[string]bool)
+	unique := requires[:0]
+	for _, req := range requires {
+		req = strings.TrimSpace(req)
+		if req == "" || seen[req] {
+			continue
+		}
+		seen[req] = true
+		unique = append(unique, req)
+	}
+	return unique, nil
+}
+
+// FromDir reads the requirements of a skill directory on disk.
+func FromDir(dir string) ([]string, error) {
+	var files [2][]byte
+	for i, name := range []string{SkillMD, SkillJSON} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("erro ao ler %s: %w", name, err)
+		}
+		files[i] = data
+	}
+	return Parse(files[0], files[1])
+}
+
+// Resolve turns a requirement of the skill from into a source key (as used
+// in sklfile.json) and the ref it asks for ("*" when none). Bare names are
+// looked up in the same repository as from and, without a tag, at the same
+// revision: their ref is then empty.
+//
+// A skill fetched from a repository may only require other remote skills:
+// file:, local@ and git+ requirements pointing at a local repository would
+// let it read anything on the machine.
+func Resolve(req, from string) (source, ref string, err error) {
+	if strings.Contains(req, "@") || strings.HasPrefix(req, "file:") || strings.HasPrefix(req, "git+") {
+		r, err := parser.Parse(req)
+		if err != nil {
+			return "", "", fmt.Errorf("dependência inválida %q: %w", req, err)
+		}
+		local := r.Provider == parser.ProviderFile || r.Provider == parser.ProviderLocal ||
+			r.Provider == parser.ProviderGit && parser.IsLocalGitURL(r.Location)
+		if local && isRemote(from) {
+			return "", "", fmt.Errorf("dependência %q recusada: uma skill remota só pode depender de skills de repositórios", req)
+		}
+		return r.Source(), orAny(r.Tag), nil
+	}
+
+	name, tag, _ := strings.Cut(req, ":")
+	if name == "" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "-") || strings.ContainsAny(name, "/\\") {
+		return "", "", fmt.Errorf("dependência inválida %q: use o nome de uma skill do mesmo repositório ou uma referência completa", req)
+	}
+
+	r, err := parser.Parse(from)
+	if err != nil {
+		return "", "", err
+	}
+	switch r.Provider {
+	case parser.ProviderLocal, parser.ProviderFile:
+		return "", "", fmt.Errorf("dependência %q: %q não vem de um repositório, use uma referência completa", req, from)
+	case parser.ProviderGit:
+		if r.Path != "" {
+			r.Path = path.Join(path.Dir(r.Path), name)
+		}
+	}
+	r.Skill = name
+	return r.Source(), tag, nil
+}
+
+// isRemote reports whether source is fetched from a repository (neither a
+// local@ nor a file: skill).
+func isRemote(source string) bool {
+	r, err := parser.Parse(source)
+	return err != nil || (r.Provider != parser.ProviderLocal && r.Provider != parser.ProviderFile)
+}
+
+// FindCycle returns a dependency cycle in graph (e.g. [a b a]), or nil.
+// graph maps each source to the sources it requires.
+func FindCycle(graph map[string][]string) []string {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var stack []string
+
+	var visit func(source string) []string
+	visit = func(source string) []string {
+		switch state[source] {
+		case visiting:
+			for i, s := range stack {
+				if s == source {
+					return append(append([]string{}, stack[i:]...), source)
+				}
+			}
+		case done:
+			return nil
+		}
+
+		state[source] = visiting
+		stack = append(stack, source)
+		for _, dep := range graph[source] {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[source] = done
+		return nil
+	}
+
+	for _, source := range sortedKeys(graph) {
+		if cycle := visit(source); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// frontMatter extracts the "requires" list from the YAML front matter of a
+// SKILL.md. Only the subset of YAML used for it is understood: a block list
+//
+//	requires:
+//	  - style-guide
+//	  - github@empresa/repo/lint:^1.0
+//
+// an inline list (requires: [style-guide, lint]) or a single value.
+func frontMatter(data []byte) ([]string, error) {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil, nil
+	}
+
+	var requires []string
+	inList := false
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(stripComment(line))
+		if trimmed == "---" || trimmed == "..." {
+			return requires, nil
+		}
+		if trimmed == "" {
+			continue
+		}
+
+		indented := line[0] == ' ' || line[0] == '\t'
+		if inList {
+			if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+				requires = append(requires, unquote(strings.TrimPrefix(trimmed, "-")))
+				continue
+			}
+			if indented {
+				return nil, fmt.Errorf("requires: esperado um item de lista (- skill), encontrado %q", trimmed)
+			}
+			inList = false
+		}
+
+		if indented {
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok || strings.TrimSpace(key) != "requires" {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		switch {
+		case value == "":
+			inList = true
+		case strings.HasPrefix(value, "["):
+			if !strings.HasSuffix(value, "]") {
+				return nil, fmt.Errorf("requires: lista não terminada: %q", value)
+			}
+			for _, item := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"), ",") {
+				if item = unquote(item); item != "" {
+					requires = append(requires, item)
+				}
+			}
+		default:
+			requires = append(requires, unquote(value))
+		}
+	}
+	return nil, fmt.Errorf("front matter sem '---' de fechamento")
+}
+
+// stripComment drops a trailing YAML comment. A '#' only starts a comment
+// after whitespace, so git+ fragments (repo.git#skill) are kept.
+func stripComment(line string) string {
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return ""
+	}
+	if i := strings.Index(line, " #"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'') {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func orAny(ref string) string {
+	if ref == "" {
+		return "*"
+	}
+	return ref
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
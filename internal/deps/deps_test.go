@@ -0,0 +1,189 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		skillMD   string
+		skillJSON string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:    "block list",
+			skillMD: "---\nname: app\nrequires:\n  - style-guide\n  - github@empresa/repo/lint:^1.0\ndescription: x\n---\n# App\n",
+			want:    []string{"style-guide", "github@empresa/repo/lint:^1.0"},
+		},
+		{
+			name:    "inline list with quotes",
+			skillMD: "---\nrequires: [style-guide, \"lint:^1\", 'git+https://h/r.git#x']\n---\n",
+			want:    []string{"style-guide", "lint:^1", "git+https://h/r.git#x"},
+		},
+		{
+			name:    "single value",
+			skillMD: "---\nrequires: style-guide\n---\n",
+			want:    []string{"style-guide"},
+		},
+		{
+			name:    "comments and CRLF",
+			skillMD: "---\r\n# comentário\r\nrequires: # dependências\r\n  - git+https://h/r.git#lint # fixada\r\n---\r\n",
+			want:    []string{"git+https://h/r.git#lint"},
+		},
+		{
+			name:    "requires of nested keys is ignored",
+			skillMD: "---\nmetadata:\n  requires: other\n---\n",
+			want:    nil,
+		},
+		{
+			name:    "no front matter",
+			skillMD: "# App\n\nrequires: style-guide\n",
+			want:    nil,
+		},
+		{
+			name:    "front matter not closed",
+			skillMD: "---\nrequires: [a]\n",
+			wantErr: true,
+		},
+		{
+			name:    "inline list not closed",
+			skillMD: "---\nrequires: [a, b\n---\n",
+			wantErr: true,
+		},
+		{
+			name:    "block list with a non-item",
+			skillMD: "---\nrequires:\n  - a\n  b\n---\n",
+			wantErr: true,
+		},
+		{
+			name:      "skill.json",
+			skillJSON: `{"requires": ["style-guide", "lint:^1"]}`,
+			want:      []string{"style-guide", "lint:^1"},
+		},
+		{
+			name:      "invalid skill.json",
+			skillJSON: `{"requires": "style-guide"}`,
+			wantErr:   true,
+		},
+		{
+			name:      "both files merged without duplicates",
+			skillMD:   "---\nrequires:\n  - style-guide\n  - lint\n  - style-guide\n---\n",
+			skillJSON: `{"requires": ["lint", " ", "docs"]}`,
+			want:      []string{"style-guide", "lint", "docs"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(bytesOrNil(tt.skillMD), bytesOrNil(tt.skillJSON))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) && len(got)+len(tt.want) > 0 {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, SkillJSON), []byte(`{"requires": ["lint"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := FromDir(dir)
+	if err != nil || !reflect.DeepEqual(got, []string{"lint"}) {
+		t.Errorf("FromDir() = %q, %v", got, err)
+	}
+
+	if got, err := FromDir(filepath.Join(dir, "missing")); err != nil || len(got) != 0 {
+		t.Errorf("FromDir(missing) = %q, %v", got, err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		req, from   string
+		source, ref string
+		wantErr     string
+	}{
+		// Bare names: same repository, same revision unless tagged
+		{req: "style-guide", from: "github@empresa/repo/app", source: "github@empresa/repo/style-guide", ref: ""},
+		{req: "style-guide:^1", from: "github@empresa/repo/app", source: "github@empresa/repo/style-guide", ref: "^1"},
+		{req: "lint", from: "git+https://h.com/r.git#skills/app", source: "git+https://h.com/r.git#skills/lint", ref: ""},
+
+		// Full references
+		{req: "github@outra/repo/lint:v2.0.0", from: "github@empresa/repo/app", source: "github@outra/repo/lint", ref: "v2.0.0"},
+		{req: "github@outra/repo/lint", from: "github@empresa/repo/app", source: "github@outra/repo/lint", ref: "*"},
+		{req: "git+https://h.com/outro.git#lint", from: "github@empresa/repo/app", source: "git+https://h.com/outro.git#lint", ref: "*"},
+		{req: "local@notes", from: "local@app", source: "local@notes", ref: "*"},
+		{req: "file:../notes", from: "file:skills/app", source: "file:../notes", ref: "*"},
+
+		// Remote skills can't reach the machine
+		{req: "file:/etc", from: "github@empresa/repo/app", wantErr: "recusada"},
+		{req: "local@notes", from: "git+https://h.com/r.git#app", wantErr: "recusada"},
+		{req: "git+file:///home/user/secret-repo#x", from: "github@empresa/repo/app", wantErr: "recusada"},
+		{req: "git+file:///home/user/secret-repo#x@v1", from: "git+ssh://h.com/r.git#app", wantErr: "recusada"},
+		{req: "git+file:///home/user/repo#x", from: "local@app", source: "git+file:///home/user/repo#x", ref: "*"},
+		{req: "git+git@h.com:time/r.git#x", from: "github@empresa/repo/app", source: "git+git@h.com:time/r.git#x", ref: "*"},
+
+		// git+ URLs are validated like on the command line
+		{req: "git+-uhttps://h/r.git#x", from: "github@empresa/repo/app", wantErr: "inválida"},
+		{req: "git+ext::sh -c x#y", from: "github@empresa/repo/app", wantErr: "inválida"},
+
+		// Bare names can't leave the repository
+		{req: "../x", from: "github@empresa/repo/app", wantErr: "inválida"},
+		{req: ".hidden", from: "github@empresa/repo/app", wantErr: "inválida"},
+		{req: "-x", from: "github@empresa/repo/app", wantErr: "inválida"},
+		{req: "", from: "github@empresa/repo/app", wantErr: "inválida"},
+		{req: "notes", from: "local@app", wantErr: "não vem de um repositório"},
+	}
+	for _, tt := range tests {
+		source, ref, err := Resolve(tt.req, tt.from)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Resolve(%q, %q) error = %v, want %q", tt.req, tt.from, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Resolve(%q, %q): %v", tt.req, tt.from, err)
+			continue
+		}
+		if source != tt.source || ref != tt.ref {
+			t.Errorf("Resolve(%q, %q) = %q, %q, want %q, %q", tt.req, tt.from, source, ref, tt.source, tt.ref)
+		}
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph map[string][]string
+		want  []string
+	}{
+		{"empty", nil, nil},
+		{"chain", map[string][]string{"a": {"b"}, "b": {"c"}}, nil},
+		{"shared dependency", map[string][]string{"a": {"b", "c"}, "b": {"d"}, "c": {"d"}}, nil},
+		{"self", map[string][]string{"a": {"a"}}, []string{"a", "a"}},
+		{"loop", map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}}, []string{"a", "b", "c", "a"}},
+		{"loop below a root", map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"b"}}, []string{"b", "c", "b"}},
+	}
+	for _, tt := range tests {
+		if got := FindCycle(tt.graph); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: FindCycle() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func bytesOrNil(s string) []byte {
+	if s == "" {
+		return nil
+	}
+	return []byte(s)
+}
@@ -0,0 +1,234 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Marker identifies the files (and blocks of files) written by skl export:
+// files carrying it may be overwritten or deleted by the next export.
+const Marker = "Gerado por skl export"
+
+// Block delimiters for files shared with hand-written content (AGENTS.md,
+// copilot-instructions.md): only the text between them is replaced.
+const (
+	BlockBegin = "<!-- skl:begin -->"
+	BlockEnd   = "<!-- skl:end -->"
+)
+
+// Skill is an installed skill, as read from its SKILL.md.
+type Skill struct {
+	Name        string // directory name
+	Description string // "description" of the front matter
+	Source      string // source in sklfile.json (e.g. github@empresa/repo/skill)
+	Version     string // tag or commit installed, empty for local skills
+	Dir         string // skill directory relative to the project, with forward slashes
+	Body        string // SKILL.md without the front matter
+}
+
+// File is an artifact generated by a format, relative to the project.
+type File struct {
+	Path    string
+	Content string
+	Block   bool // Content goes between BlockBegin and BlockEnd, keeping the rest of the file
+}
+
+// Format turns the installed skills into the files an assistant reads.
+type Format interface {
+	// Description says what is generated, for help and errors.
+	Description() string
+
+	// Files returns the artifacts for skills (sorted by name).
+	Files(skills []Skill) []File
+
+	// Owned returns glob patterns of the whole files the format writes, so
+	// the generated ones no longer produced can be deleted.
+	Owned() []string
+}
+
+// registry holds all known formats.
+var registry = map[string]Format{
+	"agents-md": AgentsMD{},
+	"copilot":   Copilot{},
+	"cursor":    Cursor{},
+}
+
+// New returns the Format for the given name, or an error if unsupported.
+func New(name string) (Format, error) {
+	f, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("formato %q não suportado (disponíveis: %s)", name, strings.Join(Names(), ", "))
+	}
+	return f, nil
+}
+
+// Register adds a new format to the registry. Useful for testing or plugins.
+func Register(name string, f Format) {
+	registry[name] = f
+}
+
+// Names returns the registered format names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ReadSkill reads the SKILL.md of an installed skill. dir is relative to
+// the current working directory.
+func ReadSkill(dir string) (Skill, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		return Skill{}, err
+	}
+	meta, body := frontMatter(string(data))
+
+	return Skill{
+		Name:        filepath.Base(dir),
+		Description: meta["description"],
+		Dir:         filepath.ToSlash(dir),
+		Body:        strings.TrimSpace(body),
+	}, nil
+}
+
+// Write writes files (relative to the current working directory) and
+// deletes the generated files matching owned that are not among them.
+// It returns the paths written and removed.
+func Write(files []File, owned []string) (written, removed []string, err error) {
+	keep := make(map[string]bool)
+	for _, f := range files {
+		keep[filepath.Clean(f.Path)] = true
+
+		content := f.Content
+		if f.Block {
+			if content, err = mergeBlock(f.Path, f.Content); err != nil {
+				return written, removed, err
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+			return written, removed, err
+		}
+		if err := os.WriteFile(f.Path, []byte(content), 0o644); err != nil {
+			return written, removed, err
+		}
+		written = append(written, f.Path)
+	}
+
+	for _, pattern := range owned {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return written, removed, err
+		}
+		for _, path := range matches {
+			if keep[filepath.Clean(path)] || !generated(path) {
+				continue
+			}
+			if err := os.Remove(path); err != nil {
+				return written, removed, err
+			}
+			removed = append(removed, filepath.ToSlash(path))
+		}
+	}
+	return written, removed, nil
+}
+
+// mergeBlock returns the content of path with the text between the block
+// markers replaced by block. Without markers, the block is appended.
+func mergeBlock(path, block string) (string, error) {
+	wrapped := BlockBegin + "\n" + strings.TrimRight(block, "\n") + "\n" + BlockEnd + "\n"
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return wrapped, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	current := string(data)
+
+	begin := strings.Index(current, BlockBegin)
+	end := strings.Index(current, BlockEnd)
+	switch {
+	case begin < 0 && end < 0:
+		if current != "" && !strings.HasSuffix(current, "\n") {
+			current += "\n"
+		}
+		if current != "" {
+			current += "\n"
+		}
+		return current + wrapped, nil
+	case begin < 0 || end < begin:
+		return "", fmt.Errorf("%s: marcadores %s e %s fora de ordem ou incompletos", path, BlockBegin, BlockEnd)
+	}
+
+	rest := strings.TrimPrefix(current[end+len(BlockEnd):], "\n")
+	return current[:begin] + wrapped + rest, nil
+}
+
+// generated reports whether the file at path was written by skl export.
+func generated(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && bytes.Contains(data, []byte(Marker))
+}
+
+// frontMatter splits a SKILL.md into the top-level scalar keys of its YAML
+// front matter and the body after it. Folded and literal block scalars
+// (description: >) are joined into a single line.
+func frontMatter(data string) (map[string]string, string) {
+	meta := make(map[string]string)
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	if !strings.HasPrefix(data, "---\n") {
+		return meta, data
+	}
+
+	lines := strings.Split(data, "\n")
+	block := "" // key of the block scalar being read
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "---" || trimmed == "..." {
+			return meta, strings.Join(lines[i+1:], "\n")
+		}
+
+		indented := line != "" && (line[0] == ' ' || line[0] == '\t')
+		if block != "" && (indented || trimmed == "") {
+			if trimmed != "" {
+				meta[block] = strings.TrimSpace(meta[block] + " " + trimmed)
+			}
+			continue
+		}
+		block = ""
+		if indented || trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if strings.HasPrefix(value, ">") || strings.HasPrefix(value, "|") {
+			block = key
+			meta[key] = ""
+			continue
+		}
+		meta[key] = unquote(value)
+	}
+
+	// No closing "---": not front matter after all
+	return make(map[string]string), data
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'') {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
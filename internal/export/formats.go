@@ -0,0 +1,120 @@
+package export
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Cursor writes one rule per skill in .cursor/rules/<skill>.mdc. The rules
+// are not always applied: Cursor picks them by their description.
+type Cursor struct{}
+
+func (Cursor) Description() string {
+	return "regras do Cursor em .cursor/rules/<skill>.mdc"
+}
+
+func (Cursor) Files(skills []Skill) []File {
+	files := make([]File, 0, len(skills))
+	for _, s := range skills {
+		var b strings.Builder
+		fmt.Fprintf(&b, "---\n")
+		fmt.Fprintf(&b, "description: %q\n", s.Description)
+		fmt.Fprintf(&b, "globs:\n")
+		fmt.Fprintf(&b, "alwaysApply: false\n")
+		fmt.Fprintf(&b, "---\n\n")
+		fmt.Fprintf(&b, "<!-- %s a partir de %s/SKILL.md — não edite, execute: skl export --format cursor -->\n", Marker, s.Dir)
+		fmt.Fprintf(&b, "<!-- Arquivos da skill: %s/ -->\n\n", s.Dir)
+		fmt.Fprintf(&b, "%s\n", s.Body)
+
+		files = append(files, File{Path: path.Join(".cursor/rules", s.Name+".mdc"), Content: b.String()})
+	}
+	return files
+}
+
+func (Cursor) Owned() []string {
+	return []string{".cursor/rules/*.mdc"}
+}
+
+// Copilot writes the instructions of every skill into
+// .github/copilot-instructions.md, which Copilot always reads.
+type Copilot struct{}
+
+func (Copilot) Description() string {
+	return "instruções do GitHub Copilot em .github/copilot-instructions.md"
+}
+
+func (Copilot) Files(skills []Skill) []File {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!-- %s — não edite este bloco, execute: skl export --format copilot -->\n", Marker)
+	fmt.Fprintf(&b, "## Skills do projeto\n")
+	for _, s := range skills {
+		fmt.Fprintf(&b, "\n### %s\n\n", s.Name)
+		if s.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", s.Description)
+		}
+		fmt.Fprintf(&b, "Arquivos da skill: `%s/`\n\n", s.Dir)
+		fmt.Fprintf(&b, "%s\n", demote(s.Body))
+	}
+	return []File{{Path: ".github/copilot-instructions.md", Content: b.String(), Block: true}}
+}
+
+func (Copilot) Owned() []string { return nil }
+
+// AgentsMD writes an index of the skills into AGENTS.md: the name,
+// description and location of each one, for agents to read on demand.
+type AgentsMD struct{}
+
+func (AgentsMD) Description() string {
+	return "índice das skills no AGENTS.md"
+}
+
+func (AgentsMD) Files(skills []Skill) []File {
+	return []File{{Path: "AGENTS.md", Content: Index(skills, "execute: skl export --format agents-md"), Block: true}}
+}
+
+func (AgentsMD) Owned() []string { return nil }
+
+// Index lists the name, description, source and location of each skill as
+// Markdown. note tells how the block is regenerated.
+func Index(skills []Skill, note string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!-- %s — não edite este bloco, %s -->\n", Marker, note)
+	fmt.Fprintf(&b, "## Skills\n\n")
+	if len(skills) == 0 {
+		fmt.Fprintf(&b, "Nenhuma skill instalada.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Antes de executar uma tarefa coberta por uma skill, leia o SKILL.md dela.\n\n")
+	for _, s := range skills {
+		fmt.Fprintf(&b, "- **%s** (`%s/SKILL.md`)", s.Name, s.Dir)
+		if s.Description != "" {
+			fmt.Fprintf(&b, ": %s", s.Description)
+		}
+		fmt.Fprintf(&b, "\n")
+		if s.Source != "" {
+			origin := s.Source
+			if s.Version != "" {
+				origin += " @ " + s.Version
+			}
+			fmt.Fprintf(&b, "  - Origem: `%s`\n", origin)
+		}
+	}
+	return b.String()
+}
+
+// demote pushes the Markdown headings of a skill body two levels down, so
+// they nest under the heading of the skill.
+func demote(body string) string {
+	lines := strings.Split(body, "\n")
+	fence := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			fence = !fence
+		}
+		if !fence && strings.HasPrefix(line, "#") {
+			lines[i] = "##" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
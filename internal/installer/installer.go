@@ -2,187 +2,361 @@ package installer
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/rduarte/skl/internal/cache"
+	"github.com/rduarte/skl/internal/deps"
+	"github.com/rduarte/skl/internal/manifest"
+	"github.com/rduarte/skl/internal/semver"
 )
 
-const skillsDir = ".agent/skills"
+// Options describes a single skill installation.
+type Options struct {
+	Skill     string // directory name under SkillsDir (skill name or alias)
+	SkillsDir string // relative to the current directory (default .agent/skills)
+	Force     bool   // replace the skill if it is already installed
+
+	// Remote sources
+	CloneURL     string
+	RepoURL      string
+	RepoSkill    string // skill name inside the repo, when installed under an alias
+	Tag          string
+	OverridePath string // explicit in-repo path of the skill (optional)
+
+	// Patch, when set, is a patch file (git diff format, paths relative to
+	// the skill directory) applied on top of the skill before it is swapped
+	// in. Relative paths are resolved against the current working directory.
+	Patch string
+
+	// LocalPath, when set, copies the skill from the filesystem instead
+	// (e.g. a sibling checkout). Relative paths are resolved against the
+	// current working directory.
+	LocalPath string
+
+	// Out receives progress messages (defaults to os.Stdout).
+	Out io.Writer
+}
+
+func (o Options) out() io.Writer {
+	if o.Out == nil {
+		return os.Stdout
+	}
+	return o.Out
+}
 
-// Install clones the given repo using sparse-checkout and copies only the
-// skill subdirectory into .agent/skills/<skill> relative to the current
-// working directory. If force is true, an existing skill is removed first.
-func Install(cloneURL, repoURL, skill, tag, overridePath string, force bool) error {
+// Install copies a skill into <SkillsDir>/<skill> relative to the current
+// working directory. Remote skills are fetched through the local clone cache
+// and only the skill subdirectory is checked out. The new copy is staged and
+// swapped in atomically: if opts.Force is true an existing skill is replaced,
+// and it is kept intact if anything fails along the way. It returns the
+// commit the skill was checked out from (empty for opts.LocalPath), which is
+// what sklfile.lock must record even if the ref has moved since.
+func Install(opts Options) (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("erro ao obter diretório atual: %w", err)
+		return "", fmt.Errorf("erro ao obter diretório atual: %w", err)
 	}
 
-	destDir := filepath.Join(cwd, skillsDir, skill)
+	destDir, err := SkillPath(opts.SkillsDir, opts.Skill)
+	if err != nil {
+		return "", err
+	}
 
-	// Check if skill already exists locally
-	if _, err := os.Stat(destDir); err == nil {
-		if !force {
-			return fmt.Errorf("skill %q já existe em %s (remova manualmente para reinstalar)", skill, destDir)
+	// Check if skill already exists locally. In force mode the existing
+	// copy is only replaced once the new one is fully staged.
+	if _, err := os.Stat(destDir); err == nil && !opts.Force {
+		return "", fmt.Errorf("skill %q já existe em %s (remova manualmente para reinstalar)", opts.Skill, destDir)
+	}
+
+	var skillSrc, via, commit string
+	if opts.LocalPath != "" {
+		skillSrc = opts.LocalPath
+		if !filepath.IsAbs(skillSrc) {
+			skillSrc = filepath.Join(cwd, skillSrc)
 		}
-		// Force mode: remove existing skill
-		if err := os.RemoveAll(destDir); err != nil {
-			return fmt.Errorf("erro ao remover skill existente: %w", err)
+		if info, err := os.Stat(skillSrc); err != nil || !info.IsDir() {
+			return "", fmt.Errorf("diretório da skill não encontrado: %s", skillSrc)
+		}
+		via = skillSrc
+	} else {
+		fmt.Fprintf(opts.out(), "⬇  Baixando skill %q...\n", opts.Skill)
+
+		// Step 1: Fetch the repo into the cache and locate the skill directory
+		repoSkill := opts.RepoSkill
+		if repoSkill == "" {
+			repoSkill = opts.Skill
+		}
+		var repo *cache.Repo
+		var skillRepoPath string
+		repo, commit, skillRepoPath, err = openSkill(opts.CloneURL, opts.RepoURL, repoSkill, opts.Tag, opts.OverridePath)
+		if err != nil {
+			return "", err
 		}
-	}
 
-	// Create temp dir for the sparse clone
-	tmpDir, err := os.MkdirTemp("", "skl-clone-*")
-	if err != nil {
-		return fmt.Errorf("erro ao criar diretório temporário: %w", err)
-	}
-	defer os.RemoveAll(tmpDir)
+		// Step 2: Checkout only the skill directory into a temp work tree
+		tmpDir, err := os.MkdirTemp("", "skl-checkout-*")
+		if err != nil {
+			return "", fmt.Errorf("erro ao criar diretório temporário: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
 
-	fmt.Printf("⬇  Baixando skill %q...\n", skill)
+		if err := repo.Checkout(commit, skillRepoPath, tmpDir); err != nil {
+			return "", fmt.Errorf("erro no checkout: %w", err)
+		}
 
-	// Step 1: Clone with sparse-checkout (blob filter for speed, no file checkout yet)
-	cloneArgs := []string{
-		"clone",
-		"--filter=blob:none",
-		"--sparse",
-		"--depth=1",
-		"--no-checkout",
+		skillSrc = filepath.Join(tmpDir, skillRepoPath)
+		via = skillRepoPath
 	}
-	if tag != "" {
-		cloneArgs = append(cloneArgs, "--branch", tag)
-	}
-	cloneArgs = append(cloneArgs, cloneURL, tmpDir)
 
-	if stderr, err := runGitCapture(cloneArgs...); err != nil {
-		return classifyCloneError(stderr, repoURL, tag)
+	// Step 3: Stage the skill next to .agent/skills/<skill> and swap it in.
+	// Until the final rename, an existing installation is left untouched.
+	if err := os.MkdirAll(filepath.Dir(destDir), 0o755); err != nil {
+		return "", fmt.Errorf("erro ao criar diretório de destino: %w", err)
 	}
 
-	// Step 2: Resolve the skill directory inside the repo
-	skillRepoPath := overridePath
-	if skillRepoPath == "" {
-		// Try .agent/skills/<skill> first
-		primaryPath := filepath.Join(".agent/skills", skill)
-		if err := verifyPathExists(tmpDir, primaryPath, repoURL); err == nil {
-			skillRepoPath = primaryPath
-		} else {
-			// Try skills/<skill> as fallback
-			fallbackPath := filepath.Join("skills", skill)
-			if err := verifyPathExists(tmpDir, fallbackPath, repoURL); err == nil {
-				skillRepoPath = fallbackPath
-			} else {
-				// If both fail, return the primary error for clarity
-				return err
-			}
-		}
-	} else if strings.HasSuffix(skillRepoPath, "/SKILL.md") || skillRepoPath == "SKILL.md" {
-		skillRepoPath = filepath.Dir(skillRepoPath)
+	staged, err := os.MkdirTemp(filepath.Dir(destDir), ".skl-stage-"+opts.Skill+"-*")
+	if err != nil {
+		return "", fmt.Errorf("erro ao criar diretório temporário: %w", err)
 	}
+	defer os.RemoveAll(staged) // no-op once renamed into place
 
-	// Step 3: Sparse-checkout only the skill directory and checkout
-	if err := runGitC(tmpDir, "sparse-checkout", "set", skillRepoPath); err != nil {
-		return fmt.Errorf("erro no sparse-checkout: %w", err)
+	if err := copyDir(skillSrc, staged); err != nil {
+		return "", fmt.Errorf("erro ao copiar skill: %w", err)
 	}
-	if err := runGitC(tmpDir, "checkout"); err != nil {
-		return fmt.Errorf("erro no checkout: %w", err)
+	if info, err := os.Stat(skillSrc); err == nil {
+		_ = os.Chmod(staged, info.Mode().Perm())
 	}
 
-	// Step 4: Copy skill directory to .agent/skills/<skill>
-	skillSrc := filepath.Join(tmpDir, skillRepoPath)
-	if err := os.MkdirAll(filepath.Dir(destDir), 0o755); err != nil {
-		return fmt.Errorf("erro ao criar diretório de destino: %w", err)
+	// Step 4: Apply the project's patch overlay, if any
+	if opts.Patch != "" {
+		patch := opts.Patch
+		if !filepath.IsAbs(patch) {
+			patch = filepath.Join(cwd, patch)
+		}
+		if err := applyPatch(staged, patch); err != nil {
+			return "", fmt.Errorf(
+				"o patch %s não se aplica mais à skill %q (via %s)\n\n"+
+					"  %v\n\n"+
+					"  A versão instalada foi mantida. Atualize o patch (skl patch %s) ou remova-o (skl patch %s --remove).",
+				opts.Patch, opts.Skill, via, strings.ReplaceAll(err.Error(), "\n", "\n  "), opts.Skill, opts.Skill,
+			)
+		}
+		fmt.Fprintf(opts.out(), "🩹 Patch aplicado: %s\n", opts.Patch)
 	}
 
-	if err := copyDir(skillSrc, destDir); err != nil {
-		return fmt.Errorf("erro ao copiar skill: %w", err)
+	if err := replaceDir(staged, destDir); err != nil {
+		return "", fmt.Errorf("erro ao substituir skill: %w", err)
 	}
 
-	fmt.Printf("✅ Skill %q instalada em %s (via %s)\n", skill, destDir, skillRepoPath)
+	fmt.Fprintf(opts.out(), "✅ Skill %q instalada em %s (via %s)\n", opts.Skill, destDir, via)
+	return commit, nil
+}
+
+// applyPatch applies a git-format patch inside dir. Repository discovery is
+// stopped at dir, so paths in the patch are always relative to it even when
+// the project itself is a git repository.
+func applyPatch(dir, patch string) error {
+	cmd := gitCommand("apply", "--whitespace=nowarn", patch)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Env, "GIT_CEILING_DIRECTORIES="+filepath.Dir(dir))
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(stderr.String()))
+	}
 	return nil
 }
 
-// FetchFile fetches a single file from a skill directory in a remote repo.
-// It clones sparsely, reads the file, and cleans up the temp dir.
-func FetchFile(cloneURL, repoURL, skill, tag, overridePath, filename string) ([]byte, error) {
-	var skillRepoPath string
+// replaceDir moves staged into place as dest. An existing dest is first
+// renamed aside and restored if the swap fails, so a failed install never
+// leaves the project without the previous version.
+func replaceDir(staged, dest string) error {
+	var backup string
+	if _, err := os.Lstat(dest); err == nil {
+		tmp, err := os.MkdirTemp(filepath.Dir(dest), ".skl-backup-"+filepath.Base(dest)+"-*")
+		if err != nil {
+			return err
+		}
+		backup = tmp
+		if err := os.Remove(backup); err != nil {
+			return err
+		}
+		if err := os.Rename(dest, backup); err != nil {
+			return err
+		}
+	}
 
-	tmpDir, err := os.MkdirTemp("", "skl-fetch-*")
-	if err != nil {
-		return nil, fmt.Errorf("erro ao criar diretório temporário: %w", err)
+	if err := os.Rename(staged, dest); err != nil {
+		if backup != "" {
+			if restoreErr := os.Rename(backup, dest); restoreErr != nil {
+				return fmt.Errorf("%w (versão anterior preservada em %s)", err, backup)
+			}
+		}
+		return err
 	}
-	defer os.RemoveAll(tmpDir)
 
-	// Clone (no checkout)
-	cloneArgs := []string{
-		"clone",
-		"--filter=blob:none",
-		"--sparse",
-		"--depth=1",
-		"--no-checkout",
+	if backup != "" {
+		_ = os.RemoveAll(backup)
 	}
-	if tag != "" {
-		cloneArgs = append(cloneArgs, "--branch", tag)
+	return nil
+}
+
+// openSkill fetches the repo through the clone cache, resolves tag to a
+// commit and finds the skill directory inside the repo: overridePath when
+// given, otherwise .agent/skills/<skill> with skills/<skill> as fallback.
+func openSkill(cloneURL, repoURL, skill, tag, overridePath string) (*cache.Repo, string, string, error) {
+	repo, err := cache.Open(cloneURL)
+	if err != nil {
+		var gitErr *cache.GitError
+		if errors.As(err, &gitErr) {
+			return nil, "", "", classifyCloneError(gitErr.Stderr, repoURL, tag)
+		}
+		return nil, "", "", err
 	}
-	cloneArgs = append(cloneArgs, cloneURL, tmpDir)
 
-	if stderr, err := runGitCapture(cloneArgs...); err != nil {
-		return nil, classifyCloneError(stderr, repoURL, tag)
+	commit, err := repo.Resolve(tag)
+	if err != nil {
+		if tag != "" {
+			return nil, "", "", fmt.Errorf(
+				"tag %q não encontrada no repositório\n\n"+
+					"  Verifique as tags disponíveis em: %s",
+				tag, repoURL,
+			)
+		}
+		return nil, "", "", err
 	}
 
-	// Resolve the skill directory inside the repo
-	skillRepoPath = overridePath
+	skillRepoPath := overridePath
 	if skillRepoPath == "" {
 		// Try .agent/skills/<skill> first
 		primaryPath := filepath.Join(".agent/skills", skill)
-		if err := verifyPathExists(tmpDir, primaryPath, repoURL); err == nil {
+		if err := verifyPathExists(repo, commit, primaryPath, repoURL); err == nil {
 			skillRepoPath = primaryPath
 		} else {
 			// Try skills/<skill> as fallback
 			fallbackPath := filepath.Join("skills", skill)
-			if err := verifyPathExists(tmpDir, fallbackPath, repoURL); err == nil {
+			if err := verifyPathExists(repo, commit, fallbackPath, repoURL); err == nil {
 				skillRepoPath = fallbackPath
 			} else {
 				// If both fail, return the primary error for clarity
-				return nil, err
+				return nil, "", "", err
 			}
 		}
 	} else if strings.HasSuffix(skillRepoPath, "/SKILL.md") || skillRepoPath == "SKILL.md" {
 		skillRepoPath = filepath.Dir(skillRepoPath)
 	}
 
-	// Sparse-checkout and checkout
-	if err := runGitC(tmpDir, "sparse-checkout", "set", skillRepoPath); err != nil {
-		return nil, fmt.Errorf("erro no sparse-checkout: %w", err)
-	}
-	if err := runGitC(tmpDir, "checkout"); err != nil {
-		return nil, fmt.Errorf("erro no checkout: %w", err)
+	return repo, commit, skillRepoPath, nil
+}
+
+// FetchFile fetches a single file from a skill directory in a remote repo.
+// Only the requested blob is downloaded into the clone cache.
+func FetchFile(cloneURL, repoURL, skill, tag, overridePath, filename string) ([]byte, error) {
+	repo, commit, skillRepoPath, err := openSkill(cloneURL, repoURL, skill, tag, overridePath)
+	if err != nil {
+		return nil, err
 	}
 
 	// Read the requested file
-	filePath := filepath.Join(tmpDir, skillRepoPath, filename)
-	data, err := os.ReadFile(filePath)
+	filePath := path.Join(filepath.ToSlash(skillRepoPath), filename)
+	if !repo.HasPath(commit, filePath) {
+		return nil, fmt.Errorf("arquivo %q não encontrado na skill %q", filename, skill)
+	}
+
+	data, err := repo.ReadFile(commit, filePath)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("arquivo %q não encontrado na skill %q", filename, skill)
-		}
 		return nil, fmt.Errorf("erro ao ler %s: %w", filename, err)
 	}
 
 	return data, nil
 }
 
-// verifyPathExists uses "git ls-tree" to check if a path exists in the repo
-// tree before attempting sparse-checkout. This gives a clear error early.
-func verifyPathExists(repoDir, path, repoURL string) error {
-	cmd := exec.Command("git", "-C", repoDir, "ls-tree", "HEAD", path)
-	out, err := cmd.Output()
+// FetchRequires reads the dependencies a skill declares (SKILL.md front
+// matter and skill.json, see deps.Parse) at the given revision.
+func FetchRequires(cloneURL, repoURL, skill, tag, overridePath string) ([]string, error) {
+	repo, commit, skillRepoPath, err := openSkill(cloneURL, repoURL, skill, tag, overridePath)
 	if err != nil {
-		return fmt.Errorf("erro ao verificar se skill existe no repositório: %w", err)
+		return nil, err
+	}
+
+	var files [2][]byte
+	for i, name := range []string{deps.SkillMD, deps.SkillJSON} {
+		filePath := path.Join(filepath.ToSlash(skillRepoPath), name)
+		if !repo.HasPath(commit, filePath) {
+			continue
+		}
+		if files[i], err = repo.ReadFile(commit, filePath); err != nil {
+			return nil, fmt.Errorf("erro ao ler %s: %w", name, err)
+		}
+	}
+	return deps.Parse(files[0], files[1])
+}
+
+// Changes is the upstream history of a skill between two revisions.
+type Changes struct {
+	Path      string         // in-repo path of the skill
+	Commits   []cache.Commit // commits touching Path, newest first
+	DiffStat  string         // file-level summary of the changes to Path
+	Downgrade bool           // the target is older: Commits are being rolled back
+}
+
+// Changelog lists the upstream commits touching a skill between the
+// revisions from and to (commit hashes, tags or branches), plus a diffstat.
+func Changelog(cloneURL, repoURL, skill, overridePath, from, to string) (*Changes, error) {
+	repo, toCommit, skillRepoPath, err := openSkill(cloneURL, repoURL, skill, to, overridePath)
+	if err != nil {
+		return nil, err
+	}
+
+	fromCommit, err := repo.Resolve(from)
+	if err != nil {
+		return nil, fmt.Errorf("revisão %q não encontrada no repositório (o histórico foi reescrito?)", from)
+	}
+
+	changes := &Changes{Path: filepath.ToSlash(skillRepoPath)}
+	start, end := fromCommit, toCommit
+	if fromCommit != toCommit && repo.IsAncestor(toCommit, fromCommit) {
+		start, end = toCommit, fromCommit
+		changes.Downgrade = true
+	}
+
+	if changes.Commits, err = repo.Log(start, end, changes.Path); err != nil {
+		return nil, fmt.Errorf("erro ao ler histórico de %s: %w", changes.Path, err)
 	}
-	if len(strings.TrimSpace(string(out))) == 0 {
+	if changes.DiffStat, err = repo.DiffStat(fromCommit, toCommit, changes.Path); err != nil {
+		return nil, fmt.Errorf("erro ao comparar %s: %w", changes.Path, err)
+	}
+	return changes, nil
+}
+
+// DiffLocal returns a unified diff from a skill as of commit upstream to
+// its installed copy in dir. Extra args are passed to git diff.
+func DiffLocal(cloneURL, repoURL, skill, commit, overridePath, dir string, args ...string) (string, error) {
+	repo, commit, skillRepoPath, err := openSkill(cloneURL, repoURL, skill, commit, overridePath)
+	if err != nil {
+		return "", err
+	}
+
+	diff, err := repo.DiffDir(commit, filepath.ToSlash(skillRepoPath), dir, args...)
+	if err != nil {
+		return "", fmt.Errorf("erro ao comparar %s com a versão instalada: %w", skillRepoPath, err)
+	}
+	return diff, nil
+}
+
+// verifyPathExists uses "git ls-tree" to check if a path exists in the repo
+// tree before attempting the checkout. This gives a clear error early.
+func verifyPathExists(repo *cache.Repo, commit, path, repoURL string) error {
+	if !repo.HasPath(commit, path) {
 		return fmt.Errorf(
 			"skill não encontrada no repositório\n"+
 				"  Caminho esperado: %s\n\n"+
@@ -193,20 +367,12 @@ func verifyPathExists(repoDir, path, repoURL string) error {
 	return nil
 }
 
-// runGitCapture executes a git command silently, capturing stderr for error analysis.
-func runGitCapture(args ...string) (string, error) {
+// gitCommand builds a git command that never prompts for credentials,
+// so a missing token fails fast instead of hanging (e.g. in CI).
+func gitCommand(args ...string) *exec.Cmd {
 	cmd := exec.Command("git", args...)
-	var stderr bytes.Buffer
-	cmd.Stdout = nil
-	cmd.Stderr = &stderr
-	err := cmd.Run()
-	return stderr.String(), err
-}
-
-// runGit executes a git command silently (output suppressed).
-func runGit(args ...string) error {
-	_, err := runGitCapture(args...)
-	return err
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	return cmd
 }
 
 // classifyCloneError inspects git stderr and returns a user-friendly error.
@@ -234,10 +400,12 @@ func classifyCloneError(stderr, repoURL, tag string) error {
 		)
 
 	case strings.Contains(low, "permission denied") ||
-		strings.Contains(low, "could not read from remote"):
+		strings.Contains(low, "could not read from remote") ||
+		strings.Contains(low, "authentication failed") ||
+		strings.Contains(low, "terminal prompts disabled"):
 		return fmt.Errorf(
 			"acesso negado ao repositório\n\n"+
-				"  Verifique suas credenciais SSH e acesso ao repositório: %s",
+				"  Verifique suas credenciais (chave SSH ou token HTTPS) e acesso ao repositório: %s",
 			repoURL,
 		)
 
@@ -251,10 +419,16 @@ func classifyCloneError(stderr, repoURL, tag string) error {
 	}
 }
 
-// runGitC executes a git command inside a specific directory.
-func runGitC(dir string, args ...string) error {
-	fullArgs := append([]string{"-C", dir}, args...)
-	return runGit(fullArgs...)
+// Copy copies the skill directory src to dst, replacing any previous copy
+// at dst (backups of modified skills, copies in extra target directories).
+func Copy(src, dst string) error {
+	if err := os.RemoveAll(dst); err != nil {
+		return fmt.Errorf("erro ao remover cópia anterior: %w", err)
+	}
+	if err := copyDir(src, dst); err != nil {
+		return fmt.Errorf("erro ao copiar %s para %s: %w", src, dst, err)
+	}
+	return nil
 }
 
 // copyDir recursively copies src directory to dst.
@@ -298,7 +472,8 @@ func copyDir(src, dst string) error {
 
 // ResolveRef uses "git ls-remote" to find the exact commit hash for a given ref (branch, tag or *).
 func ResolveRef(cloneURL, gitRef string) (string, error) {
-	args := []string{"ls-remote", cloneURL}
+	// "--" keeps a URL starting with "-" from being read as an option
+	args := []string{"ls-remote", "--", cloneURL}
 
 	target := gitRef
 	if target == "" || target == "*" {
@@ -307,7 +482,7 @@ func ResolveRef(cloneURL, gitRef string) (string, error) {
 
 	args = append(args, target)
 
-	cmd := exec.Command("git", args...)
+	cmd := gitCommand(args...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -330,27 +505,69 @@ func ResolveRef(cloneURL, gitRef string) (string, error) {
 	return parts[0], nil
 }
 
-// DiscoverRemoteSkills lists directories inside .agent/skills/ and skills/ in a remote repo.
-func DiscoverRemoteSkills(cloneURL, tag string) ([]string, error) {
-	tmpDir, err := os.MkdirTemp("", "skl-discover-*")
+// ListTags returns the tags of a remote repository mapped to the commit
+// they point to (annotated tags are peeled).
+func ListTags(cloneURL string) (map[string]string, error) {
+	cmd := gitCommand("ls-remote", "--tags", "--", cloneURL)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("erro ao listar tags remotas: %v (detalhe: %s)", err, strings.TrimSpace(stderr.String()))
+	}
+
+	tags := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		// Format is <hash>\trefs/tags/<tag>[^{}]
+		hash, name, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		name = strings.TrimPrefix(name, "refs/tags/")
+		if tag, peeled := strings.CutSuffix(name, "^{}"); peeled {
+			tags[tag] = hash
+		} else if _, seen := tags[name]; !seen {
+			tags[name] = hash
+		}
+	}
+	return tags, nil
+}
+
+// ResolveVersion picks the highest remote tag satisfying a semver range
+// (e.g. "^1.2") and returns it with its commit hash.
+func ResolveVersion(cloneURL, constraint string) (tag, hash string, err error) {
+	r, err := semver.ParseRange(constraint)
 	if err != nil {
-		return nil, err
+		return "", "", err
 	}
-	defer os.RemoveAll(tmpDir)
 
-	cloneArgs := []string{
-		"clone",
-		"--filter=blob:none",
-		"--sparse",
-		"--depth=1",
-		"--no-checkout",
+	tags, err := ListTags(cloneURL)
+	if err != nil {
+		return "", "", err
 	}
-	if tag != "" {
-		cloneArgs = append(cloneArgs, "--branch", tag)
+
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
 	}
-	cloneArgs = append(cloneArgs, cloneURL, tmpDir)
 
-	if _, err := runGitCapture(cloneArgs...); err != nil {
+	tag, ok := r.Max(names)
+	if !ok {
+		return "", "", fmt.Errorf("nenhuma tag do repositório remoto satisfaz %q", constraint)
+	}
+	return tag, tags[tag], nil
+}
+
+// DiscoverRemoteSkills lists directories inside .agent/skills/ and skills/ in a remote repo.
+func DiscoverRemoteSkills(cloneURL, tag string) ([]string, error) {
+	repo, err := cache.Open(cloneURL)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := repo.Resolve(tag)
+	if err != nil {
 		return nil, err
 	}
 
@@ -359,20 +576,12 @@ func DiscoverRemoteSkills(cloneURL, tag string) ([]string, error) {
 	seen := make(map[string]bool)
 
 	for _, p := range pathsToTry {
-		cmd := exec.Command("git", "-C", tmpDir, "ls-tree", "-d", "--name-only", "HEAD", p)
-		out, err := cmd.Output()
+		names, err := repo.ListDirs(commit, p)
 		if err != nil {
 			continue // Path might not exist, skip
 		}
 
-		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-		for _, line := range lines {
-			if line == "" {
-				continue
-			}
-			// ls-tree --name-only with a trailing slash in path returns names relative to that path
-			// e.g. .agent/skills/skill1 -> skill1
-			name := filepath.Base(line)
+		for _, name := range names {
 			if !seen[name] {
 				discovered = append(discovered, name)
 				seen[name] = true
@@ -410,14 +619,37 @@ func copyFile(src, dst string) error {
 	return err
 }
 
-// List returns the names of currently installed skills.
-func List() ([]string, error) {
+// SkillPath returns the absolute path of <dir>/<skill> relative to the
+// current working directory. An empty dir means .agent/skills; an absolute
+// one (e.g. ~/.claude/skills for user-level skills) is used as is.
+func SkillPath(dir, skill string) (string, error) {
+	if dir == "" {
+		dir = manifest.DefaultSkillsDir
+	}
+	if filepath.IsAbs(dir) {
+		return filepath.Join(dir, skill), nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("erro ao obter diretório atual: %w", err)
+	}
+	return filepath.Join(cwd, dir, skill), nil
+}
+
+// List returns the names of the skill directories in dir (relative to the
+// current working directory; empty means .agent/skills).
+func List(dir string) ([]string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return nil, err
 	}
 
-	dir := filepath.Join(cwd, skillsDir)
+	if dir == "" {
+		dir = manifest.DefaultSkillsDir
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(cwd, dir)
+	}
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
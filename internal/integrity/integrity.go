@@ -0,0 +1,148 @@
+package integrity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// prefix identifies the hash algorithm in every digest.
+const prefix = "sha256-"
+
+// Sum is the content fingerprint of an installed skill directory.
+type Sum struct {
+	// Digest covers the whole tree: every file path and content, sorted.
+	Digest string `json:"digest"`
+	// Files maps each relative path (with forward slashes) to its digest,
+	// so a mismatch can be narrowed down to individual files.
+	Files map[string]string `json:"files"`
+}
+
+// Report lists how a directory differs from the Sum recorded at install time.
+type Report struct {
+	Modified []string // files whose content changed
+	Missing  []string // files recorded at install time but now absent
+	Extra    []string // files present on disk but not recorded
+}
+
+// Clean reports whether the directory matches the recorded Sum.
+func (r *Report) Clean() bool {
+	return len(r.Modified) == 0 && len(r.Missing) == 0 && len(r.Extra) == 0
+}
+
+// Hash computes the Sum of every regular file and symlink under dir.
+func Hash(dir string) (*Sum, error) {
+	files := make(map[string]string)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		sum, err := hashEntry(path, d)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular integridade de %s: %w", dir, err)
+	}
+
+	return &Sum{Digest: treeDigest(files), Files: files}, nil
+}
+
+// Verify compares dir against want, file by file.
+func Verify(dir string, want *Sum) (*Report, error) {
+	got, err := Hash(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	if got.Digest == want.Digest {
+		return report, nil
+	}
+
+	for path, sum := range want.Files {
+		gotSum, ok := got.Files[path]
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, path)
+		case gotSum != sum:
+			report.Modified = append(report.Modified, path)
+		}
+	}
+	for path := range got.Files {
+		if _, ok := want.Files[path]; !ok {
+			report.Extra = append(report.Extra, path)
+		}
+	}
+
+	sort.Strings(report.Modified)
+	sort.Strings(report.Missing)
+	sort.Strings(report.Extra)
+	return report, nil
+}
+
+// hashEntry hashes a file's content, or a symlink's target.
+func hashEntry(path string, d fs.DirEntry) (string, error) {
+	h := sha256.New()
+
+	if d.Type()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		h.Write([]byte("symlink:" + target))
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+	}
+
+	return prefix + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// treeDigest combines the per-file digests in path order.
+func treeDigest(files map[string]string) string {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	for _, p := range paths {
+		b.WriteString(p)
+		b.WriteByte(0)
+		b.WriteString(files[p])
+		b.WriteByte('\n')
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return prefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,170 @@
+package integrity
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, dir string)
+		want   Report
+	}{
+		{
+			name:   "untouched",
+			change: func(t *testing.T, dir string) {},
+		},
+		{
+			name: "modified",
+			change: func(t *testing.T, dir string) {
+				write(t, dir, "SKILL.md", "# Alterada\n")
+			},
+			want: Report{Modified: []string{"SKILL.md"}},
+		},
+		{
+			name: "missing",
+			change: func(t *testing.T, dir string) {
+				remove(t, dir, "scripts/run.sh")
+			},
+			want: Report{Missing: []string{"scripts/run.sh"}},
+		},
+		{
+			name: "added",
+			change: func(t *testing.T, dir string) {
+				write(t, dir, "notes.md", "extra\n")
+			},
+			want: Report{Extra: []string{"notes.md"}},
+		},
+		{
+			name: "renamed",
+			change: func(t *testing.T, dir string) {
+				if err := os.Rename(filepath.Join(dir, "docs/guide.md"), filepath.Join(dir, "docs/guia.md")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: Report{Missing: []string{"docs/guide.md"}, Extra: []string{"docs/guia.md"}},
+		},
+		{
+			name: "symlink retargeted",
+			change: func(t *testing.T, dir string) {
+				remove(t, dir, "link")
+				if err := os.Symlink("docs/guide.md", filepath.Join(dir, "link")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: Report{Modified: []string{"link"}},
+		},
+		{
+			name: "git metadata is ignored",
+			change: func(t *testing.T, dir string) {
+				write(t, dir, ".git/HEAD", "ref: refs/heads/main\n")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := skillDir(t)
+			sum, err := Hash(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tt.change(t, dir)
+			report, err := Verify(dir, sum)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*report, tt.want) {
+				t.Errorf("Verify() = %+v, want %+v", *report, tt.want)
+			}
+			if report.Clean() != reflect.DeepEqual(tt.want, Report{}) {
+				t.Errorf("Clean() = %v", report.Clean())
+			}
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	dir := skillDir(t)
+	sum, err := Hash(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"SKILL.md", "docs/guide.md", "link", "scripts/run.sh"}
+	var got []string
+	for path, digest := range sum.Files {
+		got = append(got, path)
+		if !strings.HasPrefix(digest, prefix) {
+			t.Errorf("digest of %s = %q, want prefix %q", path, digest, prefix)
+		}
+	}
+	if !sameSet(got, want) {
+		t.Errorf("Hash() files = %q, want %q", got, want)
+	}
+
+	// The digest only depends on the content, not on where the skill lives
+	other := skillDir(t)
+	otherSum, err := Hash(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if otherSum.Digest != sum.Digest {
+		t.Errorf("same content, different digests: %s, %s", sum.Digest, otherSum.Digest)
+	}
+
+	if _, err := Hash(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Hash() of a missing directory should fail")
+	}
+}
+
+// skillDir creates a small skill with a nested file and a symlink.
+func skillDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	write(t, dir, "SKILL.md", "---\nname: test\n---\n# Test\n")
+	write(t, dir, "docs/guide.md", "guia\n")
+	write(t, dir, "scripts/run.sh", "#!/bin/sh\necho ok\n")
+	if err := os.Symlink("SKILL.md", filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func write(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func remove(t *testing.T, dir, name string) {
+	t.Helper()
+	if err := os.Remove(filepath.Join(dir, name)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]bool, len(a))
+	for _, s := range a {
+		seen[s] = true
+	}
+	for _, s := range b {
+		if !seen[s] {
+			return false
+		}
+	}
+	return true
+}
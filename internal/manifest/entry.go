@@ -0,0 +1,131 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DefaultSkillsDir is where skills are installed, relative to the project,
+// when sklfile.json declares no targets.
+const DefaultSkillsDir = ".agent/skills"
+
+// Entry is the value of a skill in sklfile.json. It accepts both the
+// original string form ("v1.2.0") and an object form:
+//
+//	{"ref": "v1.2.0", "path": "tools/x", "as": "x-legacy"}
+//
+// and is written back as a plain string whenever only Ref is set. Ref may
+// also be a semver range (e.g. "^1.2", "~1.4.0", ">=2 <3"), resolved against
+// the remote tags.
+// In sklfile.lock, Ref holds the resolved commit hash and the remaining
+// fields mirror the manifest, so skills can be found after they leave it.
+type Entry struct {
+	Ref    string `json:"ref,omitempty"`    // branch, tag or "*" (commit hash in the lock)
+	Path   string `json:"path,omitempty"`   // explicit in-repo path of the skill
+	As     string `json:"as,omitempty"`     // install directory name, instead of the skill name
+	Target string `json:"target,omitempty"` // skills directory relative to the project, instead of the manifest targets
+	Patch  string `json:"patch,omitempty"`  // patch applied after every install (see skl patch)
+	Notes  string `json:"notes,omitempty"`  // free text, ignored by skl
+
+	// Origin is only set on local@ skills ejected from a remote one
+	// (see skl eject): where their files came from.
+	Origin Origin `json:"origin,omitzero"`
+
+	// Version is only used in sklfile.lock: the tag a semver range
+	// (e.g. "^1.2") resolved to.
+	Version string `json:"version,omitempty"`
+	// Indirect is only used in sklfile.lock: the skill is not in
+	// sklfile.json, it was installed as a dependency of another skill.
+	Indirect bool `json:"indirect,omitempty"`
+}
+
+// Origin records the upstream revision a local skill was ejected from, so
+// it can still be compared with (or re-attached to) its source.
+type Origin struct {
+	Source  string `json:"source"`            // original reference (e.g. "github@empresa/repo/skill")
+	Ref     string `json:"ref,omitempty"`     // version it was pinned to in sklfile.json
+	Commit  string `json:"commit"`            // commit installed when it was ejected
+	Version string `json:"version,omitempty"` // tag of that commit, when known
+	Path    string `json:"path,omitempty"`    // explicit in-repo path of the skill
+}
+
+// entryObject has the same fields as Entry without its JSON methods.
+type entryObject Entry
+
+// UnmarshalJSON accepts either a string (the ref) or an object.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var ref string
+		if err := json.Unmarshal(data, &ref); err != nil {
+			return err
+		}
+		*e = Entry{Ref: ref}
+		return nil
+	}
+
+	var obj entryObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("entrada inválida (esperado texto ou objeto): %w", err)
+	}
+	*e = Entry(obj)
+	return nil
+}
+
+// MarshalJSON writes the compact string form when only Ref is set.
+func (e Entry) MarshalJSON() ([]byte, error) {
+	if e == (Entry{Ref: e.Ref}) {
+		return json.Marshal(e.Ref)
+	}
+	return json.Marshal(entryObject(e))
+}
+
+// DirName returns the directory name the skill is installed under:
+// the alias when set, otherwise the skill name from source.
+func (e Entry) DirName(source string) string {
+	if e.As != "" {
+		return e.As
+	}
+	return SkillName(source)
+}
+
+// CheckDirName checks that name can be used as a skill directory: a single
+// path segment, so a skill is never installed (or removed) outside its
+// skills directory.
+func CheckDirName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, "/\\\x00") {
+		return fmt.Errorf("nome de diretório inválido: %q (use um único nome, sem \"/\", \"..\" ou \".\" no início)", name)
+	}
+	return nil
+}
+
+// Validate checks the fields of the entry of source that end up in paths:
+// the directory name (alias or skill name) and the in-repo path.
+func (e Entry) Validate(source string) error {
+	if err := CheckDirName(e.DirName(source)); err != nil {
+		return err
+	}
+	if e.Path != "" {
+		if strings.HasPrefix(e.Path, "/") || strings.HasPrefix(e.Path, "-") || strings.Contains(e.Path, "\\") {
+			return fmt.Errorf("caminho inválido: %q (use um caminho relativo à raiz do repositório)", e.Path)
+		}
+		for _, part := range strings.Split(e.Path, "/") {
+			if part == ".." {
+				return fmt.Errorf("caminho inválido: %q (\"..\" não é permitido)", e.Path)
+			}
+		}
+	}
+	return nil
+}
+
+// Locked returns a copy of e with Ref replaced by the resolved commit hash,
+// as stored in sklfile.lock. version is the tag a semver range resolved to
+// (empty for plain branches and tags).
+func (e Entry) Locked(hash, version string) Entry {
+	e.Ref = hash
+	e.Version = version
+	e.Notes = ""
+	return e
+}
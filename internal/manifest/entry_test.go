@@ -0,0 +1,159 @@
+package manifest
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEntryJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string // value in sklfile.json
+		entry Entry
+		out   string // written back
+	}{
+		{"string", `"v1.2.0"`, Entry{Ref: "v1.2.0"}, `"v1.2.0"`},
+		{"range", `"^1.2"`, Entry{Ref: "^1.2"}, `"^1.2"`},
+		{"empty string", `""`, Entry{}, `""`},
+		{"object with only ref", `{"ref": "main"}`, Entry{Ref: "main"}, `"main"`},
+		{
+			"object",
+			`{"ref": "v1.2.0", "path": "tools/x", "as": "x-legacy", "target": ".claude/skills"}`,
+			Entry{Ref: "v1.2.0", Path: "tools/x", As: "x-legacy", Target: ".claude/skills"},
+			`{"ref":"v1.2.0","path":"tools/x","as":"x-legacy","target":".claude/skills"}`,
+		},
+		{
+			"notes keep the object form",
+			`{"ref": "*", "notes": "fixada até o fim do projeto"}`,
+			Entry{Ref: "*", Notes: "fixada até o fim do projeto"},
+			`{"ref":"*","notes":"fixada até o fim do projeto"}`,
+		},
+		{
+			"lock fields",
+			`{"ref": "0123abc", "version": "v1.1.0", "indirect": true}`,
+			Entry{Ref: "0123abc", Version: "v1.1.0", Indirect: true},
+			`{"ref":"0123abc","version":"v1.1.0","indirect":true}`,
+		},
+		{
+			"origin",
+			`{"ref": "*", "origin": {"source": "github@e/r/s", "commit": "0123abc"}}`,
+			Entry{Ref: "*", Origin: Origin{Source: "github@e/r/s", Commit: "0123abc"}},
+			`{"ref":"*","origin":{"source":"github@e/r/s","commit":"0123abc"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e Entry
+			if err := json.Unmarshal([]byte(tt.in), &e); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", tt.in, err)
+			}
+			if e != tt.entry {
+				t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.in, e, tt.entry)
+			}
+
+			out, err := json.Marshal(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tt.out {
+				t.Errorf("Marshal() = %s, want %s", out, tt.out)
+			}
+
+			// Reading what was written gives the same entry back
+			var again Entry
+			if err := json.Unmarshal(out, &again); err != nil || again != e {
+				t.Errorf("round trip = %+v, %v, want %+v", again, err, e)
+			}
+		})
+	}
+}
+
+func TestEntryJSONErrors(t *testing.T) {
+	for _, in := range []string{`1`, `true`, `["v1"]`, `{"ref": 1}`, `{"indirect": "sim"}`} {
+		var e Entry
+		if err := json.Unmarshal([]byte(in), &e); err == nil {
+			t.Errorf("Unmarshal(%s) = %+v, want an error", in, e)
+		}
+	}
+}
+
+func TestManifestJSON(t *testing.T) {
+	in := `{"skills": {"github@e/r/a": "v1", "github@e/r/b": {"ref": "*", "as": "b2"}}}`
+	var m Manifest
+	if err := json.Unmarshal([]byte(in), &m); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(&m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"skills":{"github@e/r/a":"v1","github@e/r/b":{"ref":"*","as":"b2"}}}`
+	if string(out) != want {
+		t.Errorf("Marshal() = %s, want %s", out, want)
+	}
+}
+
+func TestEntryValidate(t *testing.T) {
+	tests := []struct {
+		source string
+		entry  Entry
+		valid  bool
+	}{
+		{"github@e/r/skill", Entry{Ref: "v1"}, true},
+		{"github@e/r/skill", Entry{As: "skill-legacy"}, true},
+		{"github@e/r/skill", Entry{Path: "tools/skill"}, true},
+		{"github@e/r/skill", Entry{Path: "tools/./skill"}, true},
+		{"local@my-skill", Entry{}, true},
+
+		// The directory name is a single, visible path segment
+		{"github@e/r/skill", Entry{As: "../skill"}, false},
+		{"github@e/r/skill", Entry{As: "a/b"}, false},
+		{"github@e/r/skill", Entry{As: `a\b`}, false},
+		{"github@e/r/skill", Entry{As: ".."}, false},
+		{"github@e/r/skill", Entry{As: ".hidden"}, false},
+		{"github@e/r/skill", Entry{As: "a\x00b"}, false},
+		{"local@..", Entry{}, false},
+		{"local@.", Entry{}, false},
+
+		// The in-repo path stays inside the repository
+		{"github@e/r/skill", Entry{Path: "/etc"}, false},
+		{"github@e/r/skill", Entry{Path: "../../x"}, false},
+		{"github@e/r/skill", Entry{Path: "tools/../../x"}, false},
+		{"github@e/r/skill", Entry{Path: `tools\x`}, false},
+		{"github@e/r/skill", Entry{Path: "--upload-pack=x"}, false},
+	}
+	for _, tt := range tests {
+		err := tt.entry.Validate(tt.source)
+		if (err == nil) != tt.valid {
+			t.Errorf("%+v.Validate(%q) = %v, want valid = %v", tt.entry, tt.source, err, tt.valid)
+		}
+	}
+}
+
+func TestCheckTarget(t *testing.T) {
+	tests := []struct {
+		dir      string
+		absolute bool
+		valid    bool
+	}{
+		{".agent/skills", false, true},
+		{".claude/skills", false, true},
+		{"a/../b", false, true},
+		{"/home/u/.claude/skills", true, true},
+
+		{"", false, false},
+		{".", false, false},
+		{"./", false, false},
+		{"..", false, false},
+		{"../x", false, false},
+		{"a/../../x", false, false},
+		{"/home/u/.claude/skills", false, false},
+		{"../x", true, false},
+	}
+	for _, tt := range tests {
+		err := CheckTarget(tt.dir, tt.absolute)
+		if (err == nil) != tt.valid {
+			t.Errorf("CheckTarget(%q, %v) = %v, want valid = %v", tt.dir, tt.absolute, err, tt.valid)
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/rduarte/skl/internal/integrity"
 )
 
 const FileName = "sklfile.json"
@@ -14,37 +16,35 @@ const LockFileName = "sklfile.lock"
 
 // Manifest represents the sklfile.json file.
 // Keys are full skill references (e.g. "bitbucket@user/repo/skill"),
-// values are the git ref (branch or tag, e.g. "master", "v1.2.0").
+// values are entries holding the git ref (branch or tag, e.g. "master",
+// "v1.2.0") and optional install settings (see Entry).
 type Manifest struct {
-	Skills map[string]string `json:"skills"`
+	// Targets are the skills directories, relative to the project, that
+	// every skill without its own "target" is installed in (default
+	// .agent/skills). The first one holds the copy skl tracks, the others
+	// get a copy of it, e.g. for agents reading skills from .claude/skills.
+	Targets []string `json:"targets,omitempty"`
+
+	// Index is a Markdown file, relative to the project, listing the
+	// installed skills for the agents (e.g. "AGENTS.md"). When set, it is
+	// regenerated after install, update, remove and setup.
+	Index string `json:"index,omitempty"`
+
+	Skills map[string]Entry `json:"skills"`
+
+	// Integrity is only used in sklfile.lock: the content digest of each
+	// skill directory, recorded right after it was installed.
+	Integrity map[string]*integrity.Sum `json:"integrity,omitempty"`
+
+	// Requires is only used in sklfile.lock: the skills each skill depends
+	// on (declared in its SKILL.md or skill.json), by source.
+	Requires map[string][]string `json:"requires,omitempty"`
 }
 
 // Load reads the manifest from sklfile.json in the current directory.
 // Returns an empty manifest if the file does not exist.
 func Load() (*Manifest, error) {
-	path, err := filePath()
-	if err != nil {
-		return nil, err
-	}
-
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return &Manifest{Skills: make(map[string]string)}, nil
-		}
-		return nil, fmt.Errorf("erro ao ler %s: %w", FileName, err)
-	}
-
-	var m Manifest
-	if err := json.Unmarshal(data, &m); err != nil {
-		return nil, fmt.Errorf("erro ao interpretar %s: %w", FileName, err)
-	}
-
-	if m.Skills == nil {
-		m.Skills = make(map[string]string)
-	}
-
-	return &m, nil
+	return loadFile(FileName)
 }
 
 // Save writes the manifest to sklfile.json in the current directory.
@@ -70,12 +70,68 @@ func (m *Manifest) Save() error {
 
 // Add registers a skill in the manifest and saves it.
 // source is the full reference (e.g. "bitbucket@user/repo/skill").
-// ref is the git ref (branch or tag).
-func (m *Manifest) Add(source, ref string) error {
-	m.Skills[source] = ref
+// entry holds the git ref (branch or tag) and install settings.
+func (m *Manifest) Add(source string, entry Entry) error {
+	m.Skills[source] = entry
 	return m.Save()
 }
 
+// SkillsDirs returns the skills directories entry is installed in: its own
+// target or the targets of m. The first one is the tracked copy.
+func (m *Manifest) SkillsDirs(entry Entry) []string {
+	if entry.Target != "" {
+		return []string{entry.Target}
+	}
+	if len(m.Targets) > 0 {
+		return m.Targets
+	}
+	return []string{DefaultSkillsDir}
+}
+
+// FindAll returns the sources matching name: the source itself (e.g.
+// "github@empresa/repo/skill") or the directory name it is installed under.
+// Skills from different repositories may share a directory name when they
+// are installed in different target directories.
+func (m *Manifest) FindAll(name string) []string {
+	if _, ok := m.Skills[name]; ok {
+		return []string{name}
+	}
+	var sources []string
+	for _, source := range m.SortedSources() {
+		if m.Skills[source].DirName(source) == name {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// SetIntegrity records the content digest of an installed skill.
+func (m *Manifest) SetIntegrity(source string, sum *integrity.Sum) {
+	if m.Integrity == nil {
+		m.Integrity = make(map[string]*integrity.Sum)
+	}
+	m.Integrity[source] = sum
+}
+
+// SetRequires records the dependencies of a skill.
+func (m *Manifest) SetRequires(source string, requires []string) {
+	if m.Requires == nil {
+		m.Requires = make(map[string][]string)
+	}
+	if len(requires) == 0 {
+		delete(m.Requires, source)
+		return
+	}
+	m.Requires[source] = requires
+}
+
+// Remove drops a skill, its integrity record and its dependency list.
+func (m *Manifest) Remove(source string) {
+	delete(m.Skills, source)
+	delete(m.Integrity, source)
+	delete(m.Requires, source)
+}
+
 // SortedSources returns skill source keys sorted alphabetically.
 func (m *Manifest) SortedSources() []string {
 	sources := make([]string, 0, len(m.Skills))
@@ -91,6 +147,106 @@ func LoadLock() (*Manifest, error) {
 	return loadFile(LockFileName)
 }
 
+// LoadIn reads sklfile.json from dir instead of the current directory.
+func LoadIn(dir string) (*Manifest, error) {
+	return loadFileIn(dir, FileName)
+}
+
+// LoadLockIn reads sklfile.lock from dir instead of the current directory.
+func LoadLockIn(dir string) (*Manifest, error) {
+	return loadFileIn(dir, LockFileName)
+}
+
+// FindRoot returns the project directory for dir: the nearest directory,
+// from dir up, holding a sklfile.json. The search stops at the root of a git
+// repository (a directory with .git); without either, it is dir itself.
+func FindRoot(dir string) string {
+	for current := dir; ; {
+		if _, err := os.Stat(filepath.Join(current, FileName)); err == nil {
+			return current
+		}
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return current
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return dir
+		}
+		current = parent
+	}
+}
+
+// CheckTarget checks a skills directory of "targets" or "target": it must be
+// relative and stay inside the project. Absolute directories (e.g.
+// ~/.claude/skills) are only accepted when absolute is set, for the
+// user-level manifest.
+func CheckTarget(dir string, absolute bool) error {
+	if isAbs(dir) {
+		if absolute {
+			return nil
+		}
+		return fmt.Errorf("diretório de destino %q deve ser relativo ao projeto (caminhos absolutos só nas skills globais, -g)", dir)
+	}
+	if !insideProject(dir) {
+		return fmt.Errorf("diretório de destino %q deve ficar dentro do projeto", dir)
+	}
+	return nil
+}
+
+// CheckIndex checks the "index" file the same way: skl writes to it after
+// every change, so it must be relative and stay inside the project, unless
+// absolute is set (user-level manifest).
+func CheckIndex(path string, absolute bool) error {
+	if isAbs(path) {
+		if absolute {
+			return nil
+		}
+		return fmt.Errorf("índice %q deve ser relativo ao projeto (caminhos absolutos só nas skills globais, -g)", path)
+	}
+	if !insideProject(path) {
+		return fmt.Errorf("índice %q deve ficar dentro do projeto", path)
+	}
+	return nil
+}
+
+// isAbs reports whether path is absolute, on this system or as a slash path.
+func isAbs(path string) bool {
+	return filepath.IsAbs(path) || strings.HasPrefix(path, "/")
+}
+
+// insideProject reports whether the relative path names something below
+// the project root (not the root itself).
+func insideProject(path string) bool {
+	clean := filepath.Clean(filepath.FromSlash(path))
+	return path != "" && clean != "." && clean != ".." && !strings.HasPrefix(clean, ".."+string(filepath.Separator))
+}
+
+// isGlobalDir reports whether dir is the root of the user-level skills.
+func isGlobalDir(dir string) bool {
+	global, err := GlobalDir()
+	if err != nil {
+		return false
+	}
+	a, errA := os.Stat(dir)
+	b, errB := os.Stat(global)
+	return errA == nil && errB == nil && os.SameFile(a, b)
+}
+
+// GlobalDir returns the root of the user-level skills, shared by every
+// project: $XDG_DATA_HOME/skl (e.g. ~/.local/share/skl), holding its own
+// sklfile.json and sklfile.lock.
+func GlobalDir() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("erro ao localizar diretório de dados: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "skl"), nil
+}
+
 // SaveLock writes the lock file (sklfile.lock).
 func (m *Manifest) SaveLock() error {
 	return m.saveFile(LockFileName)
@@ -98,7 +254,15 @@ func (m *Manifest) SaveLock() error {
 
 // SkillName extracts the skill name (last path segment) from a source key.
 // e.g. "bitbucket@servicos-1doc/1doc-apis/1doc-api-expert" → "1doc-api-expert"
+// For git+ sources the name comes from the fragment:
+// e.g. "git+https://host/time/skills.git#tools/data-analyzer" → "data-analyzer"
 func SkillName(source string) string {
+	source = strings.TrimPrefix(source, "local@")
+	if strings.HasPrefix(source, "git+") {
+		if i := strings.LastIndex(source, "#"); i >= 0 {
+			source = source[i+1:]
+		}
+	}
 	parts := strings.Split(source, "/")
 	if len(parts) == 0 {
 		return source
@@ -119,12 +283,15 @@ func loadFile(name string) (*Manifest, error) {
 	if err != nil {
 		return nil, fmt.Errorf("erro ao obter diretório atual: %w", err)
 	}
+	return loadFileIn(cwd, name)
+}
 
-	path := filepath.Join(cwd, name)
+func loadFileIn(dir, name string) (*Manifest, error) {
+	path := filepath.Join(dir, name)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return &Manifest{Skills: make(map[string]string)}, nil
+			return &Manifest{Skills: make(map[string]Entry)}, nil
 		}
 		return nil, fmt.Errorf("erro ao ler %s: %w", name, err)
 	}
@@ -135,7 +302,31 @@ func loadFile(name string) (*Manifest, error) {
 	}
 
 	if m.Skills == nil {
-		m.Skills = make(map[string]string)
+		m.Skills = make(map[string]Entry)
+	}
+
+	// Aliases, paths, targets and the index come from a file anyone can
+	// commit: never let them point outside the project (absolute paths are
+	// only accepted in the user-level manifest)
+	global := isGlobalDir(dir)
+	if m.Index != "" {
+		if err := CheckIndex(m.Index, global); err != nil {
+			return nil, fmt.Errorf("erro em %s: %w", name, err)
+		}
+	}
+	for _, target := range m.Targets {
+		if err := CheckTarget(target, global); err != nil {
+			return nil, fmt.Errorf("erro em %s: %w", name, err)
+		}
+	}
+	for source, entry := range m.Skills {
+		err := entry.Validate(source)
+		if err == nil && entry.Target != "" {
+			err = CheckTarget(entry.Target, global)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro em %s, skill %q: %w", name, source, err)
+		}
 	}
 
 	return &m, nil
@@ -0,0 +1,80 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadInPaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string // empty when the manifest is valid
+	}{
+		{"index", `{"index": "AGENTS.md", "skills": {}}`, ""},
+		{"nested index", `{"index": "docs/skills.md", "skills": {}}`, ""},
+		{"index outside", `{"index": "../outside.txt", "skills": {}}`, "dentro do projeto"},
+		{"index hidden outside", `{"index": "docs/../../outside.txt", "skills": {}}`, "dentro do projeto"},
+		{"index is the root", `{"index": ".", "skills": {}}`, "dentro do projeto"},
+		{"absolute index", `{"index": "/tmp/t/abs.txt", "skills": {}}`, "relativo ao projeto"},
+		{"targets", `{"targets": [".agent/skills", ".claude/skills"], "skills": {}}`, ""},
+		{"target outside", `{"targets": ["../x"], "skills": {}}`, "dentro do projeto"},
+		{"absolute target", `{"targets": ["/home/u/skills"], "skills": {}}`, "relativo ao projeto"},
+		{"skill target outside", `{"skills": {"local@a": {"ref": "*", "target": "../x"}}}`, "dentro do projeto"},
+		{"alias outside", `{"skills": {"local@a": {"ref": "*", "as": "../x"}}}`, "inválido"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_DATA_HOME", t.TempDir())
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, FileName), []byte(tt.json), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadIn(dir)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("LoadIn() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadIn() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadInGlobalAbsolutePaths(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	dir, err := GlobalDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"targets": ["/home/u/.claude/skills"], "index": "/home/u/AGENTS.md", "skills": {}}`
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mf, err := LoadIn(dir)
+	if err != nil {
+		t.Fatalf("LoadIn(global) error = %v", err)
+	}
+	if mf.Index != "/home/u/AGENTS.md" {
+		t.Errorf("Index = %q", mf.Index)
+	}
+
+	// The global manifest still can't climb out with a relative path
+	data = `{"index": "../../outside.txt", "skills": {}}`
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadIn(dir); err == nil {
+		t.Error("LoadIn(global) should reject a relative index outside the directory")
+	}
+}
@@ -2,17 +2,34 @@ package parser
 
 import (
 	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
 
+// Providers that are not backed by the provider registry.
+const (
+	ProviderLocal = "local" // local@<skill>: untracked folder already on disk
+	ProviderGit   = "git"   // git+<url>#<skill>[@ref]: arbitrary git remote
+	ProviderFile  = "file"  // file:<path>: skill directory on the filesystem
+)
+
 // SkillRef holds all the parsed components of a skill reference.
 type SkillRef struct {
 	Provider string // e.g. "github", "bitbucket"
-	User     string // e.g. "empresa"
+	User     string // e.g. "empresa" or "grupo/subgrupo" (GitLab)
 	Repo     string // e.g. "repo-skills"
 	Skill    string // e.g. "data-analyzer"
 	Tag      string // e.g. "v1.2.0" (empty if not specified)
+
+	// Location is the clone URL for git+ references or the filesystem
+	// path (as written) for file: references.
+	Location string
+	// Path is the explicit in-repo path of the skill for git+ references
+	// whose fragment contains slashes (e.g. #tools/data-analyzer).
+	Path string
 }
 
 // RepoRef holds parsed components of a repository reference.
@@ -24,25 +41,37 @@ type RepoRef struct {
 }
 
 // pattern matches: <provider>@<user>/<repo>/<skill>[:tag]
+// <user> may span several segments to express nested groups
+// (e.g. gitlab@group/subgroup/repo/skill); the last two segments
+// are always <repo> and <skill>. The tag may be a semver range
+// such as ^1.2 or ~1.4.0.
 var pattern = regexp.MustCompile(
-	`^([a-zA-Z0-9-]+)@([a-zA-Z0-9._-]+)/([a-zA-Z0-9._-]+)/([a-zA-Z0-9._-]+)(?::([a-zA-Z0-9._-]+))?$`,
+	`^([a-zA-Z0-9-]+)@([a-zA-Z0-9._-]+(?:/[a-zA-Z0-9._-]+)*)/([a-zA-Z0-9._-]+)/([a-zA-Z0-9._-]+)(?::([a-zA-Z0-9._^~-]+))?$`,
 )
 
 // repoPattern matches: <provider>@<user>/<repo>[:tag]
+// As in pattern, <user> may contain nested groups.
 var repoPattern = regexp.MustCompile(
-	`^([a-zA-Z0-9-]+)@([a-zA-Z0-9._-]+)/([a-zA-Z0-9._-]+)(?::([a-zA-Z0-9._-]+))?$`,
+	`^([a-zA-Z0-9-]+)@([a-zA-Z0-9._-]+(?:/[a-zA-Z0-9._-]+)*)/([a-zA-Z0-9._-]+)(?::([a-zA-Z0-9._-]+))?$`,
 )
 
 // Parse takes a raw skill reference string and returns a SkillRef.
 func Parse(raw string) (*SkillRef, error) {
 	raw = strings.TrimSuffix(raw, "/")
 
+	if strings.HasPrefix(raw, "git+") {
+		return parseGit(raw)
+	}
+	if strings.HasPrefix(raw, "file:") {
+		return parseFile(raw)
+	}
+
 	// Special case: local@skill-name
 	if strings.HasPrefix(raw, "local@") {
 		parts := strings.Split(raw, "@")
-		if len(parts) == 2 && parts[1] != "" {
+		if len(parts) == 2 && parts[1] != "" && !strings.Contains(parts[1], "/") {
 			return &SkillRef{
-				Provider: "local",
+				Provider: ProviderLocal,
 				Skill:    parts[1],
 			}, nil
 		}
@@ -51,11 +80,19 @@ func Parse(raw string) (*SkillRef, error) {
 	matches := pattern.FindStringSubmatch(raw)
 	if matches == nil {
 		return nil, fmt.Errorf(
-			"formato inválido: %q\nFormato esperado: <provider>@<user>/<repo>/<skill>[:tag] ou local@<skill>\nExemplo: github@empresa/repo-skills/data-analyzer:v1.2.0 ou local@my-skill",
+			"formato inválido: %q\nFormato esperado: <provider>@<user>/<repo>/<skill>[:tag], git+<url>#<skill>[@ref], file:<caminho> ou local@<skill>\nExemplo: github@empresa/repo-skills/data-analyzer:v1.2.0 ou local@my-skill",
 			raw,
 		)
 	}
 
+	// git@ and file@ would be routed as git+ and file: references
+	if reserved(matches[1]) {
+		return nil, fmt.Errorf("formato inválido: %q (%q não é um provider; use local@<skill>, git+<url>#<skill> ou file:<caminho>)", raw, matches[1])
+	}
+	if dotSegment(matches[2] + "/" + matches[3] + "/" + matches[4]) {
+		return nil, fmt.Errorf("formato inválido: %q (\".\" e \"..\" não são nomes válidos)", raw)
+	}
+
 	ref := &SkillRef{
 		Provider: matches[1],
 		User:     matches[2],
@@ -67,6 +104,108 @@ func Parse(raw string) (*SkillRef, error) {
 	return ref, nil
 }
 
+// parseGit parses git+<url>#<skill>[@ref], where <skill> may be an in-repo
+// path (e.g. git+https://host/any/path.git#tools/data-analyzer@v1.0.0).
+func parseGit(raw string) (*SkillRef, error) {
+	rest := strings.TrimPrefix(raw, "git+")
+	i := strings.LastIndex(rest, "#")
+	if i <= 0 || i == len(rest)-1 {
+		return nil, fmt.Errorf(
+			"formato inválido: %q\nFormato esperado: git+<url>#<skill>[@ref]\nExemplo: git+https://git.empresa.com/time/skills.git#data-analyzer@v1.2.0",
+			raw,
+		)
+	}
+
+	location, fragment := rest[:i], rest[i+1:]
+	if err := ValidateGitURL(location); err != nil {
+		return nil, fmt.Errorf("formato inválido: %q (%w)", raw, err)
+	}
+	skillPath, tag, _ := strings.Cut(fragment, "@")
+	skillPath = strings.Trim(skillPath, "/")
+	if skillPath == "" {
+		return nil, fmt.Errorf("formato inválido: %q (skill não informada após '#')", raw)
+	}
+
+	ref := &SkillRef{
+		Provider: ProviderGit,
+		Skill:    path.Base(skillPath),
+		Tag:      tag,
+		Location: location,
+	}
+	if strings.Contains(skillPath, "/") {
+		ref.Path = skillPath
+	}
+	return ref, nil
+}
+
+// scpPattern matches scp-style SSH locations ([user@]host:path).
+var scpPattern = regexp.MustCompile(`^([A-Za-z0-9._-]+@)?[A-Za-z0-9][A-Za-z0-9.-]*:[^:]+$`)
+
+// ValidateGitURL checks the location of a git+ reference before it is handed
+// to git: only https://, ssh://, file:// and scp-style URLs are accepted, and
+// nothing that git could read as an option (e.g. --upload-pack=...).
+func ValidateGitURL(location string) error {
+	if location == "" || strings.HasPrefix(location, "-") {
+		return fmt.Errorf("URL do repositório inválida: %q", location)
+	}
+	if strings.ContainsAny(location, " \t\r\n") {
+		return fmt.Errorf("URL do repositório não pode conter espaços: %q", location)
+	}
+
+	for _, scheme := range []string{"https://", "ssh://", "file://"} {
+		if !strings.HasPrefix(location, scheme) {
+			continue
+		}
+		u, err := url.Parse(location)
+		if err != nil {
+			return fmt.Errorf("URL do repositório inválida: %q: %w", location, err)
+		}
+		if scheme != "file://" && (u.Host == "" || strings.HasPrefix(u.Host, "-")) {
+			return fmt.Errorf("URL do repositório sem host: %q", location)
+		}
+		if u.Path == "" || u.Path == "/" {
+			return fmt.Errorf("URL do repositório sem caminho: %q", location)
+		}
+		return nil
+	}
+
+	// Any other scheme (http://, git://...) is not scp-style either
+	if !strings.Contains(location, "://") && scpPattern.MatchString(location) {
+		return nil
+	}
+	return fmt.Errorf("URL do repositório não suportada: %q (use https://, ssh://, file:// ou usuario@host:caminho)", location)
+}
+
+// IsLocalGitURL reports whether a git+ location reads a repository on this
+// machine: a file:// URL or a bare filesystem path.
+func IsLocalGitURL(location string) bool {
+	if strings.HasPrefix(location, "file://") {
+		return true
+	}
+	if strings.HasPrefix(location, "https://") || strings.HasPrefix(location, "ssh://") {
+		return false
+	}
+	return !scpPattern.MatchString(location)
+}
+
+// parseFile parses file:<path>, a skill directory on the local filesystem.
+// The path is kept as written so relative paths stay reproducible.
+func parseFile(raw string) (*SkillRef, error) {
+	location := strings.TrimPrefix(raw, "file:")
+	if location == "" {
+		return nil, fmt.Errorf(
+			"formato inválido: %q\nFormato esperado: file:<caminho>\nExemplo: file:../skills-repo/data-analyzer",
+			raw,
+		)
+	}
+
+	return &SkillRef{
+		Provider: ProviderFile,
+		Skill:    filepath.Base(filepath.Clean(location)),
+		Location: location,
+	}, nil
+}
+
 // ParseRepo takes a raw repository reference string and returns a RepoRef.
 func ParseRepo(raw string) (*RepoRef, error) {
 	raw = strings.TrimSuffix(raw, "/")
@@ -78,6 +217,13 @@ func ParseRepo(raw string) (*RepoRef, error) {
 		)
 	}
 
+	if reserved(matches[1]) {
+		return nil, fmt.Errorf("formato de repositório inválido: %q (%q não é um provider)", raw, matches[1])
+	}
+	if dotSegment(matches[2] + "/" + matches[3]) {
+		return nil, fmt.Errorf("formato de repositório inválido: %q (\".\" e \"..\" não são nomes válidos)", raw)
+	}
+
 	return &RepoRef{
 		Provider: matches[1],
 		User:     matches[2],
@@ -86,11 +232,50 @@ func ParseRepo(raw string) (*RepoRef, error) {
 	}, nil
 }
 
+// reserved reports whether name is one of the providers handled by the
+// parser itself, which can't be used in <provider>@ references.
+func reserved(name string) bool {
+	return name == ProviderLocal || name == ProviderGit || name == ProviderFile
+}
+
+// dotSegment reports whether a slash-separated path has a "." or ".."
+// segment, which would point elsewhere once used in URLs and directories.
+func dotSegment(p string) bool {
+	for _, seg := range strings.Split(p, "/") {
+		if seg == "." || seg == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// Source returns the reference without its tag, as used for the keys of
+// sklfile.json (e.g. "github@empresa/repo-skills/data-analyzer").
+func (r *SkillRef) Source() string {
+	switch r.Provider {
+	case ProviderLocal:
+		return "local@" + r.Skill
+	case ProviderFile:
+		return "file:" + r.Location
+	case ProviderGit:
+		fragment := r.Skill
+		if r.Path != "" {
+			fragment = r.Path
+		}
+		return "git+" + r.Location + "#" + fragment
+	}
+	return fmt.Sprintf("%s@%s/%s/%s", r.Provider, r.User, r.Repo, r.Skill)
+}
+
 // String returns a human-readable representation of the SkillRef.
 func (r *SkillRef) String() string {
-	s := fmt.Sprintf("%s@%s/%s/%s", r.Provider, r.User, r.Repo, r.Skill)
+	s := r.Source()
 	if r.Tag != "" {
-		s += ":" + r.Tag
+		if r.Provider == ProviderGit {
+			s += "@" + r.Tag
+		} else {
+			s += ":" + r.Tag
+		}
 	}
 	return s
 }
@@ -0,0 +1,201 @@
+package parser
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want SkillRef
+	}{
+		{"github@empresa/repo-skills/data-analyzer", SkillRef{Provider: "github", User: "empresa", Repo: "repo-skills", Skill: "data-analyzer"}},
+		{"github@empresa/repo-skills/data-analyzer:v1.2.0", SkillRef{Provider: "github", User: "empresa", Repo: "repo-skills", Skill: "data-analyzer", Tag: "v1.2.0"}},
+		{"gitlab@grupo/subgrupo/repo/skill:^1.2", SkillRef{Provider: "gitlab", User: "grupo/subgrupo", Repo: "repo", Skill: "skill", Tag: "^1.2"}},
+		{"bitbucket@time/repo/skill:~1.4.0", SkillRef{Provider: "bitbucket", User: "time", Repo: "repo", Skill: "skill", Tag: "~1.4.0"}},
+		{"github@empresa/repo/skill/", SkillRef{Provider: "github", User: "empresa", Repo: "repo", Skill: "skill"}},
+		{"local@my-skill", SkillRef{Provider: ProviderLocal, Skill: "my-skill"}},
+
+		// git+<url>#<skill>[@ref]
+		{"git+https://git.empresa.com/time/skills.git#data-analyzer", SkillRef{Provider: ProviderGit, Skill: "data-analyzer", Location: "https://git.empresa.com/time/skills.git"}},
+		{"git+https://git.empresa.com/time/skills.git#data-analyzer@v1.2.0", SkillRef{Provider: ProviderGit, Skill: "data-analyzer", Tag: "v1.2.0", Location: "https://git.empresa.com/time/skills.git"}},
+		{"git+ssh://git@h.com:2222/time/skills.git#tools/lint@main", SkillRef{Provider: ProviderGit, Skill: "lint", Tag: "main", Location: "ssh://git@h.com:2222/time/skills.git", Path: "tools/lint"}},
+		{"git+git@h.com:time/skills.git#lint", SkillRef{Provider: ProviderGit, Skill: "lint", Location: "git@h.com:time/skills.git"}},
+		{"git+file:///srv/repos/skills.git#/lint/", SkillRef{Provider: ProviderGit, Skill: "lint", Location: "file:///srv/repos/skills.git"}},
+
+		// file:<path>, kept as written
+		{"file:../skills-repo/data-analyzer", SkillRef{Provider: ProviderFile, Skill: "data-analyzer", Location: "../skills-repo/data-analyzer"}},
+		{"file:/opt/skills/lint", SkillRef{Provider: ProviderFile, Skill: "lint", Location: "/opt/skills/lint"}},
+		{"file:./lint/", SkillRef{Provider: ProviderFile, Skill: "lint", Location: "./lint"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.raw)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.raw, err)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.raw, *got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"data-analyzer",
+		"github@empresa/repo",
+		"github@empresa/repo/skill:v1 2",
+		"github@empresa/../skill",
+		"local@",
+		"file:",
+
+		// Providers handled by the parser itself
+		"git@user/repo/skill",
+		"file@user/repo/skill",
+		"local@user/repo/skill",
+
+		// git+ without a skill
+		"git+https://h.com/r.git",
+		"git+https://h.com/r.git#",
+		"git+#skill",
+		"git+https://h.com/r.git#/",
+
+		// git+ locations git would read as options or transports
+		"git+-uhttps://h.com/r.git#x",
+		"git+--upload-pack=touch /tmp/pwned#x",
+		"git+ext::sh -c touch% /tmp/pwned#x",
+		"git+fd::3#x",
+		"git+https://-oProxyCommand=x/r.git#x",
+		"git+ssh://-oProxyCommand=x/r.git#x",
+		"git+https://h.com#x",
+		"git+http://h.com/r.git#x",
+		"git+/srv/repos/skills.git#x",
+	}
+	for _, raw := range tests {
+		if ref, err := Parse(raw); err == nil {
+			t.Errorf("Parse(%q) = %+v, want an error", raw, *ref)
+		}
+	}
+}
+
+func TestValidateGitURL(t *testing.T) {
+	tests := []struct {
+		location string
+		valid    bool
+	}{
+		{"https://github.com/empresa/skills.git", true},
+		{"https://h.com:8443/grupo/sub/skills", true},
+		{"ssh://git@h.com/time/skills.git", true},
+		{"ssh://git@h.com:2222/time/skills.git", true},
+		{"git@h.com:time/skills.git", true},
+		{"h.com:time/skills.git", true},
+		{"file:///srv/repos/skills.git", true},
+
+		{"", false},
+		{"-uhttps://h.com/r.git", false},
+		{"--upload-pack=x", false},
+		{"ext::sh -c x", false},
+		{"ext::sh", false},
+		{"fd::3", false},
+		{"https://h.com/r.git --upload-pack=x", false},
+		{"https://h.com/r\n.git", false},
+		{"https://-oProxyCommand=x/r.git", false},
+		{"ssh:///r.git", false},
+		{"https://h.com", false},
+		{"https://h.com/", false},
+		{"file://", false},
+		{"http://h.com/r.git", false},
+		{"git://h.com/r.git", false},
+		{"/srv/repos/skills.git", false},
+		{"../skills.git", false},
+		{"-h.com:r.git", false},
+	}
+	for _, tt := range tests {
+		err := ValidateGitURL(tt.location)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateGitURL(%q) = %v, want valid = %v", tt.location, err, tt.valid)
+		}
+	}
+}
+
+func TestIsLocalGitURL(t *testing.T) {
+	tests := []struct {
+		location string
+		local    bool
+	}{
+		{"file:///home/user/secret-repo", true},
+		{"/home/user/secret-repo", true},
+		{"../secret-repo", true},
+		{"https://h.com/r.git", false},
+		{"ssh://git@h.com/r.git", false},
+		{"git@h.com:time/r.git", false},
+	}
+	for _, tt := range tests {
+		if got := IsLocalGitURL(tt.location); got != tt.local {
+			t.Errorf("IsLocalGitURL(%q) = %v, want %v", tt.location, got, tt.local)
+		}
+	}
+}
+
+func TestParseRepo(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want RepoRef
+	}{
+		{"github@rmyndharis/antigravity-skills", RepoRef{Provider: "github", User: "rmyndharis", Repo: "antigravity-skills"}},
+		{"gitlab@grupo/sub/repo:v1.0.0", RepoRef{Provider: "gitlab", User: "grupo/sub", Repo: "repo", Tag: "v1.0.0"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRepo(tt.raw)
+		if err != nil {
+			t.Errorf("ParseRepo(%q): %v", tt.raw, err)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("ParseRepo(%q) = %+v, want %+v", tt.raw, *got, tt.want)
+		}
+	}
+
+	for _, raw := range []string{"github", "github@repo", "git@user/repo", "file@user/repo", "github@../repo", "github@empresa/.."} {
+		if _, err := ParseRepo(raw); err == nil {
+			t.Errorf("ParseRepo(%q) should fail", raw)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		raw, source string
+	}{
+		{"github@empresa/repo/skill", "github@empresa/repo/skill"},
+		{"github@empresa/repo/skill:v1.2.0", "github@empresa/repo/skill"},
+		{"gitlab@grupo/sub/repo/skill:^1", "gitlab@grupo/sub/repo/skill"},
+		{"local@my-skill", "local@my-skill"},
+		{"file:../skills/lint", "file:../skills/lint"},
+		{"git+https://h.com/r.git#lint", "git+https://h.com/r.git#lint"},
+		{"git+https://h.com/r.git#tools/lint@v1.0.0", "git+https://h.com/r.git#tools/lint"},
+		{"git+git@h.com:time/r.git#lint@main", "git+git@h.com:time/r.git#lint"},
+	}
+	for _, tt := range tests {
+		ref, err := Parse(tt.raw)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.raw, err)
+			continue
+		}
+		if got := ref.Source(); got != tt.source {
+			t.Errorf("Parse(%q).Source() = %q, want %q", tt.raw, got, tt.source)
+		}
+		if got := ref.String(); got != tt.raw {
+			t.Errorf("Parse(%q).String() = %q", tt.raw, got)
+		}
+
+		// The source is a valid reference on its own, parsing to the same skill
+		again, err := Parse(ref.Source())
+		if err != nil {
+			t.Errorf("Parse(%q): %v", ref.Source(), err)
+			continue
+		}
+		if again.Source() != ref.Source() || again.Tag != "" {
+			t.Errorf("Parse(%q) = %+v, not the same skill as %q", ref.Source(), *again, tt.raw)
+		}
+	}
+}
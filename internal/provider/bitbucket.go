@@ -11,6 +11,12 @@ func (Bitbucket) CloneURL(user, repo string) string {
 	return fmt.Sprintf("git@bitbucket.org:%s/%s.git", user, repo)
 }
 
+func (Bitbucket) HTTPSCloneURL(user, repo string) string {
+	return fmt.Sprintf("https://bitbucket.org/%s/%s.git", user, repo)
+}
+
+func (Bitbucket) TokenUser() string { return "x-token-auth" }
+
 func (Bitbucket) RepoURL(user, repo string) string {
 	return fmt.Sprintf("https://bitbucket.org/%s/%s", user, repo)
 }
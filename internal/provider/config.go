@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rduarte/skl/internal/parser"
+)
+
+// ConfigFileName is the user-level file declaring custom providers.
+const ConfigFileName = "providers.json"
+
+// Config represents the providers.json file, e.g.:
+//
+//	{
+//	  "providers": {
+//	    "acme": { "type": "gitea", "host": "git.acme.com" },
+//	    "corp": {
+//	      "clone_url": "git@git.corp.com:{user}/{repo}.git",
+//	      "repo_url":  "https://git.corp.com/{user}/{repo}",
+//	      "raw_url":   "https://git.corp.com/{user}/{repo}/raw/{ref}/{path}"
+//	    }
+//	  }
+//	}
+type Config struct {
+	Providers map[string]ProviderConfig `json:"providers"`
+}
+
+// ProviderConfig declares a single custom provider. Type selects a preset
+// for a known forge; any URL template set explicitly overrides the preset.
+type ProviderConfig struct {
+	Type          string `json:"type,omitempty"` // "github", "gitlab", "gitea" or "forgejo"
+	Host          string `json:"host,omitempty"`
+	CloneURL      string `json:"clone_url,omitempty"`
+	HTTPSCloneURL string `json:"https_clone_url,omitempty"`
+	RepoURL       string `json:"repo_url,omitempty"`
+	RawURL        string `json:"raw_url,omitempty"`
+	Transport     string `json:"transport,omitempty"`  // "ssh" or "https"
+	TokenUser     string `json:"token_user,omitempty"` // username paired with the token over HTTPS
+}
+
+// presets holds the URL templates used for each supported Type.
+// A plain "gitlab" entry is served by GitLab itself, whose raw URL goes
+// through the files API; the preset only applies when some template is
+// overridden, and falls back to the /-/raw/ web route.
+var presets = map[string]Template{
+	"gitlab": {
+		Clone: "git@{host}:{user}/{repo}.git",
+		HTTPS: "https://{host}/{user}/{repo}.git",
+		Repo:  "https://{host}/{user}/{repo}",
+		Raw:   "https://{host}/{user}/{repo}/-/raw/{ref}/{path}",
+		User:  "oauth2",
+	},
+	"github": {
+		Clone: "git@{host}:{user}/{repo}.git",
+		HTTPS: "https://{host}/{user}/{repo}.git",
+		Repo:  "https://{host}/{user}/{repo}",
+		Raw:   "https://{host}/{user}/{repo}/raw/{ref}/{path}",
+		User:  "x-access-token",
+	},
+	"gitea": {
+		Clone: "git@{host}:{user}/{repo}.git",
+		HTTPS: "https://{host}/{user}/{repo}.git",
+		Repo:  "https://{host}/{user}/{repo}",
+		Raw:   "https://{host}/{user}/{repo}/raw/{ref}/{path}",
+	},
+	"forgejo": {
+		Clone: "git@{host}:{user}/{repo}.git",
+		HTTPS: "https://{host}/{user}/{repo}.git",
+		Repo:  "https://{host}/{user}/{repo}",
+		Raw:   "https://{host}/{user}/{repo}/raw/{ref}/{path}",
+	},
+}
+
+// namePattern mirrors the provider part accepted by the reference parser.
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
+
+// ConfigPath returns the location of providers.json
+// (e.g. ~/.config/skl/providers.json on Linux).
+func ConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("erro ao localizar diretório de configuração: %w", err)
+	}
+	return filepath.Join(dir, "skl", ConfigFileName), nil
+}
+
+// LoadConfig reads providers.json from ConfigPath and registers every
+// provider declared in it. A missing file is not an error.
+func LoadConfig() error {
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	return LoadConfigFile(path)
+}
+
+// LoadConfigFile reads the given providers file and registers every
+// provider declared in it. A missing file is not an error.
+func LoadConfigFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("erro ao ler %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("erro ao interpretar %s: %w", path, err)
+	}
+
+	for name, pc := range cfg.Providers {
+		p, err := pc.build(name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if pc.Transport != "" {
+			if err := validateTransport(pc.Transport); err != nil {
+				return fmt.Errorf("%s: provider %q: %w", path, name, err)
+			}
+			transports[name] = pc.Transport
+		}
+		Register(name, p)
+	}
+
+	return nil
+}
+
+// build turns a ProviderConfig into a Provider registered under name.
+func (pc ProviderConfig) build(name string) (Provider, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("nome de provider inválido: %q", name)
+	}
+	// These names are handled by the parser itself, never by a provider
+	switch name {
+	case parser.ProviderLocal, parser.ProviderGit, parser.ProviderFile:
+		return nil, fmt.Errorf("nome de provider reservado: %q", name)
+	}
+
+	if pc.Type != "" && pc.Host == "" {
+		return nil, fmt.Errorf("provider %q: o campo \"host\" é obrigatório para o tipo %q", name, pc.Type)
+	}
+
+	if pc.Type == "gitlab" && pc.CloneURL == "" && pc.HTTPSCloneURL == "" && pc.RepoURL == "" && pc.RawURL == "" {
+		return GitLab{ID: name, Host: pc.Host}, nil
+	}
+
+	t := Template{ID: name, Host: pc.Host, User: pc.TokenUser}
+	if pc.Type != "" {
+		preset, ok := presets[pc.Type]
+		if !ok {
+			return nil, fmt.Errorf("provider %q: tipo %q não suportado (disponíveis: github, gitlab, gitea, forgejo)", name, pc.Type)
+		}
+		t.Clone, t.HTTPS, t.Repo, t.Raw = preset.Clone, preset.HTTPS, preset.Repo, preset.Raw
+		if t.User == "" {
+			t.User = preset.User
+		}
+	}
+
+	if pc.CloneURL != "" {
+		t.Clone = pc.CloneURL
+	}
+	if pc.HTTPSCloneURL != "" {
+		t.HTTPS = pc.HTTPSCloneURL
+	}
+	if pc.RepoURL != "" {
+		t.Repo = pc.RepoURL
+	}
+	if pc.RawURL != "" {
+		t.Raw = pc.RawURL
+	}
+
+	if t.Clone == "" {
+		return nil, fmt.Errorf("provider %q: informe \"type\" e \"host\" ou o template \"clone_url\"", name)
+	}
+	// The clone URL may be an scp-style SSH address: the web page (and the
+	// HTTPS clone URL derived from it) can't be guessed from it
+	if t.Repo == "" && t.HTTPS != "" {
+		t.Repo = strings.TrimSuffix(t.HTTPS, ".git")
+	}
+	if t.Repo == "" {
+		return nil, fmt.Errorf("provider %q: informe o template \"repo_url\" (ou \"https_clone_url\") junto com \"clone_url\"", name)
+	}
+
+	return t, nil
+}
@@ -11,6 +11,12 @@ func (GitHub) CloneURL(user, repo string) string {
 	return fmt.Sprintf("git@github.com:%s/%s.git", user, repo)
 }
 
+func (GitHub) HTTPSCloneURL(user, repo string) string {
+	return fmt.Sprintf("https://github.com/%s/%s.git", user, repo)
+}
+
+func (GitHub) TokenUser() string { return "x-access-token" }
+
 func (GitHub) RepoURL(user, repo string) string {
 	return fmt.Sprintf("https://github.com/%s/%s", user, repo)
 }
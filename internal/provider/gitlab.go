@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// DefaultGitLabHost is used when no host is configured for GitLab.
+const DefaultGitLabHost = "gitlab.com"
+
+// GitLab implements Provider for gitlab.com and self-hosted GitLab instances.
+// The user part may contain nested groups (e.g. "group/subgroup").
+type GitLab struct {
+	ID   string // provider name, when registered under a custom name
+	Host string // e.g. "gitlab.com" or "git.empresa.com.br"
+}
+
+func (g GitLab) Name() string {
+	if g.ID == "" {
+		return "gitlab"
+	}
+	return g.ID
+}
+
+func (g GitLab) host() string {
+	if g.Host == "" {
+		return DefaultGitLabHost
+	}
+	return g.Host
+}
+
+func (g GitLab) CloneURL(user, repo string) string {
+	return fmt.Sprintf("git@%s:%s/%s.git", g.host(), user, repo)
+}
+
+func (g GitLab) HTTPSCloneURL(user, repo string) string {
+	return fmt.Sprintf("https://%s/%s/%s.git", g.host(), user, repo)
+}
+
+func (GitLab) TokenUser() string { return "oauth2" }
+
+func (g GitLab) RepoURL(user, repo string) string {
+	return fmt.Sprintf("https://%s/%s/%s", g.host(), user, repo)
+}
+
+// RawURL uses the repository files API, which (unlike the /-/raw/ web
+// route) accepts token authentication for private projects.
+func (g GitLab) RawURL(user, repo, ref, path string) string {
+	if ref == "" {
+		ref = "main"
+	}
+	return fmt.Sprintf("https://%s/api/v4/projects/%s/repository/files/%s/raw?ref=%s",
+		g.host(), url.PathEscape(user+"/"+repo), url.PathEscape(path), url.QueryEscape(ref))
+}
@@ -1,6 +1,9 @@
 package provider
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+)
 
 // Provider knows how to build a Git clone URL for a specific hosting service.
 type Provider interface {
@@ -10,6 +13,10 @@ type Provider interface {
 	// CloneURL returns the SSH clone URL for the given user/repo.
 	CloneURL(user, repo string) string
 
+	// HTTPSCloneURL returns the HTTPS clone URL (without credentials)
+	// for the given user/repo.
+	HTTPSCloneURL(user, repo string) string
+
 	// RepoURL returns the browsable HTTPS URL for the given user/repo.
 	RepoURL(user, repo string) string
 
@@ -19,9 +26,11 @@ type Provider interface {
 }
 
 // registry holds all known providers.
+// The GitLab host can be pointed to a self-hosted instance via SKL_GITLAB_HOST.
 var registry = map[string]Provider{
 	"github":    GitHub{},
 	"bitbucket": Bitbucket{},
+	"gitlab":    GitLab{Host: os.Getenv("SKL_GITLAB_HOST")},
 }
 
 // New returns a Provider for the given name, or an error if unsupported.
@@ -0,0 +1,61 @@
+package provider
+
+import "strings"
+
+// Template implements Provider from URL templates, allowing self-hosted
+// forges (Gitea, Forgejo, GitHub Enterprise...) to be declared without code.
+// Templates may use the placeholders {host}, {user}, {repo}, {ref} and {path}.
+type Template struct {
+	ID    string // provider name used in references (e.g. "acme")
+	Host  string // e.g. "git.acme.com"
+	Clone string // e.g. "git@{host}:{user}/{repo}.git"
+	HTTPS string // e.g. "https://{host}/{user}/{repo}.git"
+	Repo  string // e.g. "https://{host}/{user}/{repo}"
+	Raw   string // e.g. "https://{host}/{user}/{repo}/raw/{ref}/{path}"
+
+	// User is the username paired with the token in HTTPS clone URLs.
+	User string
+}
+
+func (t Template) Name() string { return t.ID }
+
+func (t Template) CloneURL(user, repo string) string {
+	return t.expand(t.Clone, user, repo, "", "")
+}
+
+// HTTPSCloneURL falls back to RepoURL + ".git" when no HTTPS template is set.
+func (t Template) HTTPSCloneURL(user, repo string) string {
+	if t.HTTPS == "" {
+		return t.RepoURL(user, repo) + ".git"
+	}
+	return t.expand(t.HTTPS, user, repo, "", "")
+}
+
+func (t Template) TokenUser() string {
+	if t.User == "" {
+		return "oauth2"
+	}
+	return t.User
+}
+
+func (t Template) RepoURL(user, repo string) string {
+	return t.expand(t.Repo, user, repo, "", "")
+}
+
+func (t Template) RawURL(user, repo, ref, path string) string {
+	if ref == "" {
+		ref = "main"
+	}
+	return t.expand(t.Raw, user, repo, ref, path)
+}
+
+func (t Template) expand(tmpl, user, repo, ref, path string) string {
+	r := strings.NewReplacer(
+		"{host}", t.Host,
+		"{user}", user,
+		"{repo}", repo,
+		"{ref}", ref,
+		"{path}", path,
+	)
+	return r.Replace(tmpl)
+}
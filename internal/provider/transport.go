@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Supported clone transports.
+const (
+	TransportSSH   = "ssh"
+	TransportHTTPS = "https"
+)
+
+// transport is the global override set via --transport or SKL_TRANSPORT.
+var transport string
+
+// transports holds per-provider defaults declared in providers.json.
+var transports = map[string]string{}
+
+// SetTransport overrides the transport for every provider.
+// An empty value restores the per-provider defaults.
+func SetTransport(t string) error {
+	if t != "" {
+		if err := validateTransport(t); err != nil {
+			return err
+		}
+	}
+	transport = t
+	return nil
+}
+
+func validateTransport(t string) error {
+	if t != TransportSSH && t != TransportHTTPS {
+		return fmt.Errorf("transporte %q inválido (use %q ou %q)", t, TransportSSH, TransportHTTPS)
+	}
+	return nil
+}
+
+// TransportFor returns the transport used to clone from p. The global
+// override wins, then the provider's configured default; otherwise HTTPS
+// is used whenever a token is available and SSH in any other case.
+func TransportFor(p Provider) string {
+	if transport != "" {
+		return transport
+	}
+	if t, ok := transports[p.Name()]; ok {
+		return t
+	}
+	if Token(p) != "" {
+		return TransportHTTPS
+	}
+	return TransportSSH
+}
+
+// TokenEnv returns the environment variable holding the token for p
+// (e.g. SKL_GITHUB_TOKEN, SKL_MY_FORGE_TOKEN).
+func TokenEnv(p Provider) string {
+	name := strings.ToUpper(strings.ReplaceAll(p.Name(), "-", "_"))
+	return "SKL_" + name + "_TOKEN"
+}
+
+// Token returns the access token configured for p, or "" if none.
+func Token(p Provider) string {
+	return os.Getenv(TokenEnv(p))
+}
+
+// tokenUser is implemented by providers that require a specific username
+// alongside the token in HTTPS URLs.
+type tokenUser interface {
+	TokenUser() string
+}
+
+// CloneURLFor returns the URL used to clone user/repo from p, honoring the
+// selected transport. Over HTTPS the token, if any, is embedded in the URL;
+// use Redact before showing it to the user.
+func CloneURLFor(p Provider, user, repo string) string {
+	if TransportFor(p) != TransportHTTPS {
+		return p.CloneURL(user, repo)
+	}
+
+	raw := p.HTTPSCloneURL(user, repo)
+	token := Token(p)
+	if token == "" {
+		return raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	name := "oauth2"
+	if tu, ok := p.(tokenUser); ok {
+		name = tu.TokenUser()
+	}
+	u.User = url.UserPassword(name, token)
+	return u.String()
+}
+
+// Redact hides the credentials embedded in a clone URL.
+func Redact(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Redacted()
+}
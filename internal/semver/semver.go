@@ -0,0 +1,398 @@
+package semver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version parsed from a git tag (e.g. "v1.2.0").
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 string // pre-release suffix, e.g. "rc.1" (empty for releases)
+}
+
+// Parse parses a tag such as "v1.2.0", "1.2.0-rc.1" or "v2". Missing minor
+// and patch numbers are read as zero; build metadata (+...) is ignored.
+func Parse(s string) (Version, error) {
+	v, n, err := parsePartial(s)
+	if err != nil {
+		return Version{}, err
+	}
+	if n == 0 {
+		return Version{}, fmt.Errorf("versão inválida: %q", s)
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 when v is lower than, equal to or greater than o.
+// Pre-releases sort before the release they precede.
+func (v Version) Compare(o Version) int {
+	for _, d := range [3]int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return comparePre(v.Pre, o.Pre)
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// IsRange reports whether a manifest ref is a version constraint (e.g.
+// "^1.2", "~1.4.0", ">=2 <3", "1.x") rather than a branch or tag name.
+// "*" is not a range: it keeps meaning the default branch.
+func IsRange(ref string) bool {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return false
+	}
+	if strings.ContainsAny(ref[:1], "^~<>=") || strings.ContainsAny(ref, " |") {
+		return true
+	}
+	parts := strings.Split(strings.TrimPrefix(ref, "v"), ".")
+	if len(parts) < 2 {
+		return false
+	}
+	for _, p := range parts {
+		if !isWildcard(p) && !isNumber(p) {
+			return false
+		}
+	}
+	return isWildcard(parts[len(parts)-1])
+}
+
+// Range is a set of npm-style constraints: comparators separated by spaces
+// must all match, alternatives are separated by "||".
+type Range struct {
+	sets [][]comparator
+}
+
+type comparator struct {
+	op string // one of "<", "<=", ">", ">=", "="
+	v  Version
+}
+
+// ParseRange parses a constraint such as "^1.2", "~1.4.0", ">=2 <3",
+// "1.2 - 1.4" or "^1 || ^2".
+func ParseRange(s string) (*Range, error) {
+	r := &Range{}
+	for _, alt := range strings.Split(s, "||") {
+		set, err := parseSet(strings.Fields(alt))
+		if err != nil {
+			return nil, fmt.Errorf("restrição de versão inválida %q: %w", s, err)
+		}
+		r.sets = append(r.sets, set)
+	}
+	return r, nil
+}
+
+// Match reports whether v satisfies the range. Pre-releases only match
+// when a comparator names a pre-release of the same major.minor.patch.
+func (r *Range) Match(v Version) bool {
+	for _, set := range r.sets {
+		if matchSet(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Max returns the tag with the highest version satisfying the range.
+// Tags that are not versions are skipped.
+func (r *Range) Max(tags []string) (string, bool) {
+	var best string
+	var bestV Version
+	found := false
+	for _, tag := range tags {
+		v, err := Parse(tag)
+		if err != nil || !r.Match(v) {
+			continue
+		}
+		if !found || v.Compare(bestV) > 0 {
+			best, bestV, found = tag, v, true
+		}
+	}
+	return best, found
+}
+
+// Sort orders tags by version, lowest first. Tags that are not versions
+// are dropped.
+func Sort(tags []string) []string {
+	type tagged struct {
+		tag string
+		v   Version
+	}
+	var list []tagged
+	for _, tag := range tags {
+		if v, err := Parse(tag); err == nil {
+			list = append(list, tagged{tag, v})
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].v.Compare(list[j].v) < 0 })
+
+	sorted := make([]string, len(list))
+	for i, t := range list {
+		sorted[i] = t.tag
+	}
+	return sorted
+}
+
+// parseSet turns the space-separated tokens of one alternative into
+// plain comparators, expanding ^, ~, x-ranges and hyphen ranges.
+func parseSet(tokens []string) ([]comparator, error) {
+	if len(tokens) == 0 {
+		// An empty alternative matches any release
+		return []comparator{{op: ">=", v: Version{}}}, nil
+	}
+
+	// Hyphen range: "1.2 - 1.4"
+	if len(tokens) == 3 && tokens[1] == "-" {
+		lo, _, err := parsePartial(tokens[0])
+		if err != nil {
+			return nil, err
+		}
+		hi, n, err := parsePartial(tokens[2])
+		if err != nil {
+			return nil, err
+		}
+		set := []comparator{{">=", lo}}
+		if n == 3 {
+			set = append(set, comparator{"<=", hi})
+		} else if n > 0 {
+			set = append(set, comparator{"<", bump(hi, n)})
+		}
+		return set, nil
+	}
+
+	var set []comparator
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		// Allow a space between the operator and the version (">= 2")
+		if strings.Trim(tok, "<>=^~") == "" && i+1 < len(tokens) {
+			i++
+			tok += tokens[i]
+		}
+
+		cs, err := parseComparator(tok)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, cs...)
+	}
+	return set, nil
+}
+
+// parseComparator expands a single constraint token.
+func parseComparator(tok string) ([]comparator, error) {
+	rest := strings.TrimLeft(tok, "<>=^~")
+	op := tok[:len(tok)-len(rest)]
+	v, n, err := parsePartial(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "^":
+		if n == 0 {
+			return []comparator{{">=", Version{}}}, nil
+		}
+		// The upper bound bumps the first non-zero part given
+		upper := bump(v, 1)
+		if v.Major == 0 && n >= 2 {
+			upper = bump(v, 2)
+			if v.Minor == 0 && n == 3 {
+				upper = bump(v, 3)
+			}
+		}
+		return []comparator{{">=", v}, {"<", upper}}, nil
+	case "~":
+		if n == 0 {
+			return []comparator{{">=", Version{}}}, nil
+		}
+		upper := bump(v, 2)
+		if n == 1 {
+			upper = bump(v, 1)
+		}
+		return []comparator{{">=", v}, {"<", upper}}, nil
+	case "", "=":
+		if n == 3 {
+			return []comparator{{"=", v}}, nil
+		}
+		if n == 0 {
+			return []comparator{{">=", Version{}}}, nil
+		}
+		return []comparator{{">=", v}, {"<", bump(v, n)}}, nil
+	case ">", "<=":
+		if n == 0 {
+			if op == ">" {
+				// Nothing is greater than "any version"
+				return []comparator{{"<", Version{}}}, nil
+			}
+			return []comparator{{">=", Version{}}}, nil
+		}
+		if n < 3 {
+			// ">1.2" means ">=1.3.0", "<=1.2" means "<1.3.0"
+			if op == ">" {
+				return []comparator{{">=", bump(v, n)}}, nil
+			}
+			return []comparator{{"<", bump(v, n)}}, nil
+		}
+		return []comparator{{op, v}}, nil
+	case ">=", "<":
+		return []comparator{{op, v}}, nil
+	}
+	return nil, fmt.Errorf("operador desconhecido %q", op)
+}
+
+// parsePartial parses a possibly incomplete version ("1", "1.2", "1.x",
+// "v1.2.3-rc.1"). n is the number of numeric parts given before the first
+// wildcard.
+func parsePartial(s string) (v Version, n int, err error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.Index(s, "+"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.Index(s, "-"); i >= 0 {
+		v.Pre = s[i+1:]
+		s = s[:i]
+		if v.Pre == "" {
+			return Version{}, 0, fmt.Errorf("versão inválida: %q", s)
+		}
+	}
+	if s == "" || isWildcard(s) {
+		return v, 0, nil
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return Version{}, 0, fmt.Errorf("versão inválida: %q", s)
+	}
+
+	nums := [3]*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		if isWildcard(p) {
+			break
+		}
+		num, err := strconv.Atoi(p)
+		if err != nil || num < 0 {
+			return Version{}, 0, fmt.Errorf("versão inválida: %q", s)
+		}
+		*nums[i] = num
+		n++
+	}
+	if n < 3 {
+		v.Pre = "" // "1.2-rc" has no meaning as a partial version
+	}
+	return v, n, nil
+}
+
+// bump returns the lowest version above every version sharing the first
+// n parts of v (e.g. bump(1.2.3, 2) = 1.3.0).
+func bump(v Version, n int) Version {
+	switch n {
+	case 1:
+		return Version{Major: v.Major + 1}
+	case 2:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	default:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+}
+
+func matchSet(set []comparator, v Version) bool {
+	for _, c := range set {
+		if !c.match(v) {
+			return false
+		}
+	}
+	if v.Pre == "" {
+		return true
+	}
+
+	// Follow npm: a pre-release is only picked when explicitly asked for
+	for _, c := range set {
+		if c.v.Pre != "" && c.v.Major == v.Major && c.v.Minor == v.Minor && c.v.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparator) match(v Version) bool {
+	cmp := v.Compare(c.v)
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// comparePre orders pre-release suffixes following the semver spec.
+func comparePre(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1 // numeric identifiers sort first
+		case bErr == nil:
+			return 1
+		case as[i] != bs[i]:
+			if as[i] < bs[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+func isWildcard(s string) bool {
+	return s == "x" || s == "X" || s == "*"
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
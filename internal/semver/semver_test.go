@@ -0,0 +1,138 @@
+package semver
+
+import "testing"
+
+func TestIsRange(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{"^1.2", true},
+		{"~1.4.0", true},
+		{">=2 <3", true},
+		{">1.2", true},
+		{"<=1.2", true},
+		{"1.2 - 1.4", true},
+		{"^1 || ^2", true},
+		{"1.x", true},
+		{"v1.2.x", true},
+		{"1.2.*", true},
+		{"*", false},
+		{"", false},
+		{"main", false},
+		{"v1.2.0", false},
+		{"1.2", false},
+		{"release-1.x", false},
+	}
+	for _, tt := range tests {
+		if got := IsRange(tt.ref); got != tt.want {
+			t.Errorf("IsRange(%q) = %v, want %v", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		rng     string
+		matches []string
+		rejects []string
+	}{
+		{"^1.2", []string{"1.2.0", "v1.2.5", "1.9.9"}, []string{"1.1.9", "2.0.0", "2.0.0-rc.1"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.2.2", "0.3.0", "1.0.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.2", "0.0.4", "0.1.0"}},
+		{"^0", []string{"0.0.1", "0.9.0"}, []string{"1.0.0"}},
+		{"~1.4.0", []string{"1.4.0", "1.4.7"}, []string{"1.3.9", "1.5.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{">1.2", []string{"1.3.0", "2.0.0"}, []string{"1.2.0", "1.2.9"}},
+		{"<=1.2", []string{"1.0.0", "1.2.9"}, []string{"1.3.0"}},
+		{">1.2.3", []string{"1.2.4"}, []string{"1.2.3"}},
+		{"<=1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{">=2 <3", []string{"2.0.0", "2.9.9"}, []string{"1.9.9", "3.0.0"}},
+		{">= 2", []string{"2.0.0", "5.0.0"}, []string{"1.9.9"}},
+		{"1.2 - 1.4", []string{"1.2.0", "1.4.9"}, []string{"1.1.9", "1.5.0"}},
+		{"1.2.3 - 1.4.0", []string{"1.2.3", "1.4.0"}, []string{"1.2.2", "1.4.1"}},
+		{"1.x", []string{"1.0.0", "1.9.9"}, []string{"0.9.0", "2.0.0"}},
+		{"1.2.x", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"*", []string{"0.0.1", "9.9.9"}, []string{"1.0.0-rc.1"}},
+		{"^1 || ^3", []string{"1.5.0", "3.0.0"}, []string{"2.0.0"}},
+		{"1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		// Pre-releases only match when asked for on the same version
+		{"^1.2.0-rc.1", []string{"1.2.0-rc.1", "1.2.0-rc.2", "1.2.0", "1.3.0"}, []string{"1.2.0-beta", "1.3.0-rc.1"}},
+		{">=1.0.0-alpha <2", []string{"1.0.0-beta", "1.0.0"}, []string{"1.1.0-rc.1"}},
+	}
+	for _, tt := range tests {
+		r, err := ParseRange(tt.rng)
+		if err != nil {
+			t.Errorf("ParseRange(%q): %v", tt.rng, err)
+			continue
+		}
+		for _, tag := range tt.matches {
+			if !r.Match(mustParse(t, tag)) {
+				t.Errorf("%q should match %s", tt.rng, tag)
+			}
+		}
+		for _, tag := range tt.rejects {
+			if r.Match(mustParse(t, tag)) {
+				t.Errorf("%q should not match %s", tt.rng, tag)
+			}
+		}
+	}
+}
+
+func TestParseRangeErrors(t *testing.T) {
+	for _, rng := range []string{"^1.2.3.4", "~abc", "1.2 - abc", "=>1"} {
+		if _, err := ParseRange(rng); err == nil {
+			t.Errorf("ParseRange(%q) should fail", rng)
+		}
+	}
+}
+
+func TestMax(t *testing.T) {
+	tags := []string{"v1.0.0", "v1.2.0", "v1.10.0", "v2.0.0-rc.1", "v2.0.0", "v3.0.0-beta", "latest", "v0.3.1"}
+	tests := []struct {
+		rng  string
+		want string
+		ok   bool
+	}{
+		{"^1", "v1.10.0", true},
+		{"~1.2", "v1.2.0", true},
+		{"<=1.2", "v1.2.0", true},
+		{">1.2", "v2.0.0", true},
+		{"1.2 - 1.4", "v1.2.0", true},
+		{"1.x", "v1.10.0", true},
+		{"*", "v2.0.0", true},
+		{"^0.3", "v0.3.1", true},
+		{"^2.0.0-rc.1", "v2.0.0", true},
+		{"^3.0.0-beta", "v3.0.0-beta", true},
+		{"^4", "", false},
+	}
+	for _, tt := range tests {
+		r, err := ParseRange(tt.rng)
+		if err != nil {
+			t.Fatalf("ParseRange(%q): %v", tt.rng, err)
+		}
+		got, ok := r.Max(tags)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Max(%q) = %q, %v, want %q, %v", tt.rng, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.10.0", "2.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		lo, hi := mustParse(t, ordered[i-1]), mustParse(t, ordered[i])
+		if lo.Compare(hi) >= 0 || hi.Compare(lo) <= 0 {
+			t.Errorf("%s should sort before %s", ordered[i-1], ordered[i])
+		}
+	}
+}
+
+func mustParse(t *testing.T, s string) Version {
+	t.Helper()
+	v, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", s, err)
+	}
+	return v
+}